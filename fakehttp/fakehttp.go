@@ -0,0 +1,231 @@
+// Package fakehttp menyediakan server HTTP palsu di memori untuk menguji kode yang memakai helper
+// HTTP gocommon tanpa membuka port jaringan. Server dibangun di atas fasthttputil.InmemoryListener,
+// sehingga permintaan tetap melewati parser HTTP fasthttp yang sebenarnya.
+//
+// Contoh penggunaan:
+//
+//	func TestInquiry(t *testing.T) {
+//	    srv := fakehttp.NewServer()
+//	    defer srv.Close()
+//	    srv.Handle("POST", "/v1/inquiry").ReplyJSON(200, map[string]string{"status": "ok"})
+//
+//	    restore := srv.Install() // HTTPGetJSON/HTTPPostJSON kini mengarah ke srv
+//	    defer restore()
+//
+//	    resp, status, err := gocommon.HTTPPostJSON(srv.URL()+"/v1/inquiry", nil, req)
+//	    ...
+//	    srv.AssertExpectations(t)
+//	}
+package fakehttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	gocommon "github.com/budimanlai/go-common"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+)
+
+// DefaultURL adalah URL dasar server palsu. Host-nya tidak pernah di-resolve karena semua koneksi
+// diarahkan ke listener in-memory.
+const DefaultURL = "http://fakehttp.local"
+
+// Request adalah salinan permintaan yang diterima server.
+type Request struct {
+	Method string
+	// Path adalah path tanpa query string.
+	Path string
+	// URI adalah path beserta query string.
+	URI    string
+	Header http.Header
+	Body   []byte
+}
+
+// JSON men-decode body permintaan ke v.
+func (r Request) JSON(v interface{}) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// Server adalah server HTTP palsu di memori.
+type Server struct {
+	ln     *fasthttputil.InmemoryListener
+	srv    *fasthttp.Server
+	closed chan struct{}
+	once   sync.Once
+
+	mu        sync.Mutex
+	routes    []*Route
+	requests  []Request
+	unmatched []Request
+}
+
+// NewServer membuat dan menjalankan server palsu. Panggil Close setelah selesai.
+func NewServer() *Server {
+	s := &Server{
+		ln:     fasthttputil.NewInmemoryListener(),
+		closed: make(chan struct{}),
+	}
+	s.srv = &fasthttp.Server{Handler: s.serve}
+	go s.srv.Serve(s.ln)
+	return s
+}
+
+// Close menghentikan server. Route yang sedang menunda respons (Delay, Hang) langsung dilepas.
+func (s *Server) Close() error {
+	var err error
+	s.once.Do(func() {
+		close(s.closed)
+		err = s.ln.Close()
+	})
+	return err
+}
+
+// URL mengembalikan URL dasar server, yaitu DefaultURL.
+func (s *Server) URL() string {
+	return DefaultURL
+}
+
+// Dial membuka koneksi ke server in-memory. Alamat tujuan diabaikan; fungsi ini cocok
+// dipakai sebagai fasthttp.DialFunc.
+func (s *Server) Dial(addr string) (net.Conn, error) {
+	return s.ln.Dial()
+}
+
+// Client membuat HTTPClient yang terhubung ke server ini dengan base URL DefaultURL.
+// Opsi tambahan diterapkan setelah opsi bawaan.
+func (s *Server) Client(opts ...gocommon.HTTPClientOption) *gocommon.HTTPClient {
+	base := []gocommon.HTTPClientOption{
+		gocommon.WithDialFunc(s.Dial),
+		gocommon.WithBaseURL(DefaultURL),
+	}
+	return gocommon.NewHTTPClient(append(base, opts...)...)
+}
+
+// Install mengganti gocommon.DefaultHTTPClient dengan klien yang terhubung ke server ini, sehingga
+// HTTPRequest, HTTPGetJSON, HTTPPostJSON, dan helper global lainnya mengenai server palsu.
+// Panggil fungsi yang dikembalikan untuk memulihkan klien sebelumnya. Jangan dipakai pada tes paralel.
+func (s *Server) Install(opts ...gocommon.HTTPClientOption) (restore func()) {
+	prev := gocommon.DefaultHTTPClient
+	gocommon.DefaultHTTPClient = s.Client(opts...)
+	return func() {
+		gocommon.DefaultHTTPClient = prev
+	}
+}
+
+// Handle mendaftarkan route untuk method dan path. Method kosong atau "*" cocok dengan semua metode.
+// Route dicocokkan sesuai urutan pendaftaran; route yang kuota Times-nya habis dilewati.
+// Tanpa Reply, route membalas 200 dengan body kosong.
+func (s *Server) Handle(method, path string) *Route {
+	r := &Route{
+		server: s,
+		method: strings.ToUpper(method),
+		path:   path,
+		status: fasthttp.StatusOK,
+		header: make(http.Header),
+		times:  -1,
+	}
+	s.mu.Lock()
+	s.routes = append(s.routes, r)
+	s.mu.Unlock()
+	return r
+}
+
+// Requests mengembalikan semua permintaan yang diterima, sesuai urutan kedatangan.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Unmatched mengembalikan permintaan yang tidak cocok dengan route mana pun.
+func (s *Server) Unmatched() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.unmatched...)
+}
+
+// AssertExpectations menggagalkan tes jika ada route yang tidak dipanggil (atau tidak tepat n kali
+// untuk route dengan Times(n)), atau jika ada permintaan yang tidak cocok dengan route mana pun.
+func (s *Server) AssertExpectations(t testing.TB) {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, r := range s.routes {
+		switch {
+		case r.optional:
+		case r.times >= 0 && len(r.calls) != r.times:
+			t.Errorf("fakehttp: %s %s called %d times, want %d", r.methodName(), r.path, len(r.calls), r.times)
+		case r.times < 0 && len(r.calls) == 0:
+			t.Errorf("fakehttp: %s %s was not called", r.methodName(), r.path)
+		}
+	}
+	for _, req := range s.unmatched {
+		t.Errorf("fakehttp: unexpected request %s %s", req.Method, req.URI)
+	}
+}
+
+// serve adalah handler fasthttp untuk semua permintaan.
+func (s *Server) serve(ctx *fasthttp.RequestCtx) {
+	req := captureRequest(ctx)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	var route *Route
+	for _, r := range s.routes {
+		if r.matches(req) {
+			route = r
+			break
+		}
+	}
+	if route == nil {
+		s.unmatched = append(s.unmatched, req)
+		s.mu.Unlock()
+		ctx.SetStatusCode(fasthttp.StatusNotFound)
+		ctx.SetBodyString(fmt.Sprintf("fakehttp: no route for %s %s", req.Method, req.Path))
+		return
+	}
+	route.calls = append(route.calls, req)
+	s.mu.Unlock()
+
+	route.respond(ctx)
+}
+
+// wait menunggu selama d atau sampai server ditutup. Nilai d negatif berarti menunggu sampai ditutup.
+func (s *Server) wait(d time.Duration) {
+	if d < 0 {
+		<-s.closed
+		return
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-s.closed:
+	}
+}
+
+// captureRequest menyalin permintaan dari ctx.
+func captureRequest(ctx *fasthttp.RequestCtx) Request {
+	req := Request{
+		Method: string(ctx.Method()),
+		Path:   string(ctx.Path()),
+		URI:    string(ctx.RequestURI()),
+		Header: make(http.Header),
+		Body:   append([]byte(nil), ctx.PostBody()...),
+	}
+	for _, key := range ctx.Request.Header.PeekKeys() {
+		k := string(key)
+		for _, v := range ctx.Request.Header.PeekAll(k) {
+			req.Header.Add(k, string(v))
+		}
+	}
+	return req
+}
@@ -0,0 +1,107 @@
+package fakehttp
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	gocommon "github.com/budimanlai/go-common"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestServer_RoutesAndAssertions(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	srv.Handle("GET", "/banks").ReplyJSON(fasthttp.StatusOK, []map[string]string{{"code": "014"}})
+	transfer := srv.Handle("POST", "/transfer").Reply(fasthttp.StatusCreated, `{"id":"t1"}`).Header("X-Request-Id", "r1")
+
+	restore := srv.Install()
+	defer restore()
+
+	resp, status, err := gocommon.HTTPGetJSON(srv.URL()+"/banks", nil)
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusOK, status)
+	require.JSONEq(t, `[{"code":"014"}]`, string(resp))
+
+	resp, status, err = gocommon.HTTPPostJSON(srv.URL()+"/transfer", map[string]string{"X-Partner-Id": "p1"},
+		map[string]int{"amount": 10000})
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusCreated, status)
+	require.JSONEq(t, `{"id":"t1"}`, string(resp))
+
+	calls := transfer.Calls()
+	require.Len(t, calls, 1)
+	require.Equal(t, "p1", calls[0].Header.Get("X-Partner-Id"))
+	var body map[string]int
+	require.NoError(t, calls[0].JSON(&body))
+	require.Equal(t, 10000, body["amount"])
+
+	srv.AssertExpectations(t)
+	require.Len(t, srv.Requests(), 2)
+
+	_, status, err = gocommon.HTTPGetJSON(srv.URL()+"/missing", nil)
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusNotFound, status)
+	require.Len(t, srv.Unmatched(), 1)
+
+	rec := &recordingTB{TB: t}
+	srv.AssertExpectations(rec)
+	require.Equal(t, []string{"fakehttp: unexpected request GET /missing"}, rec.errors)
+}
+
+func TestServer_SequenceWithTimes(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	srv.Handle("GET", "/status").Reply(fasthttp.StatusServiceUnavailable, "").Times(2)
+	srv.Handle("GET", "/status").Reply(fasthttp.StatusOK, `{"ok":true}`).Times(1)
+	srv.Handle("GET", "/unused").Optional()
+
+	client := srv.Client(gocommon.WithRetryPolicy(gocommon.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+	_, status, err := client.GetJSON("/status", nil)
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusOK, status)
+	srv.AssertExpectations(t)
+}
+
+func TestServer_LatencyTimeoutAndReset(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	srv.Handle("GET", "/slow").Delay(30 * time.Millisecond)
+	srv.Handle("GET", "/hang").Hang()
+	srv.Handle("GET", "/reset").ResetConnection()
+
+	client := srv.Client(gocommon.WithTimeout(200 * time.Millisecond))
+
+	start := time.Now()
+	_, status, err := client.GetJSON("/slow", nil)
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusOK, status)
+	require.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+
+	_, _, err = client.GetJSON("/hang", nil)
+	var herr *gocommon.HTTPError
+	require.True(t, errors.As(err, &herr))
+	require.True(t, herr.IsTimeout())
+
+	_, _, err = client.GetJSON("/reset", nil)
+	require.True(t, errors.As(err, &herr))
+	require.True(t, herr.IsNetworkError())
+	require.False(t, herr.IsTimeout())
+}
+
+// recordingTB menangkap pesan Errorf tanpa menggagalkan tes.
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
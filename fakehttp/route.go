@@ -0,0 +1,153 @@
+package fakehttp
+
+import (
+	"encoding/json"
+	"net"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// Route adalah respons palsu untuk satu method dan path. Method pengatur dapat dirangkai:
+//
+//	srv.Handle("GET", "/banks").Delay(50 * time.Millisecond).ReplyJSON(200, banks)
+//
+// Route harus selesai dikonfigurasi sebelum permintaan pertama dikirim.
+type Route struct {
+	server *Server
+	method string
+	path   string
+
+	status   int
+	header   map[string][]string
+	body     []byte
+	handler  fasthttp.RequestHandler
+	delay    time.Duration
+	hang     bool
+	reset    bool
+	times    int
+	optional bool
+
+	// calls dilindungi oleh server.mu.
+	calls []Request
+}
+
+// Reply mengatur kode status dan body respons.
+func (r *Route) Reply(status int, body string) *Route {
+	r.status = status
+	r.body = []byte(body)
+	return r
+}
+
+// ReplyJSON mengatur kode status dan body respons hasil marshal v, dengan Content-Type application/json.
+// Fungsi ini panic jika v tidak dapat di-marshal.
+func (r *Route) ReplyJSON(status int, v interface{}) *Route {
+	body, err := json.Marshal(v)
+	if err != nil {
+		panic("fakehttp: marshal reply: " + err.Error())
+	}
+	r.status = status
+	r.body = body
+	return r.Header("Content-Type", "application/json")
+}
+
+// Header menambahkan header respons.
+func (r *Route) Header(key, value string) *Route {
+	r.header[key] = append(r.header[key], value)
+	return r
+}
+
+// HandlerFunc memakai handler fasthttp sendiri untuk membangun respons, misalnya untuk respons
+// yang bergantung pada isi permintaan. Delay dan Times tetap berlaku.
+func (r *Route) HandlerFunc(h fasthttp.RequestHandler) *Route {
+	r.handler = h
+	return r
+}
+
+// Delay menunda respons selama d untuk mensimulasikan latensi.
+func (r *Route) Delay(d time.Duration) *Route {
+	r.delay = d
+	return r
+}
+
+// Hang membuat server tidak pernah membalas sampai Close dipanggil, untuk mensimulasikan timeout.
+func (r *Route) Hang() *Route {
+	r.hang = true
+	return r
+}
+
+// ResetConnection menutup koneksi tanpa mengirim respons, untuk mensimulasikan koneksi yang diputus server.
+func (r *Route) ResetConnection() *Route {
+	r.reset = true
+	return r
+}
+
+// Times membatasi route agar hanya cocok n kali. Setelah itu permintaan dicocokkan ke route berikutnya,
+// sehingga beberapa route dengan path sama dapat membentuk urutan respons, misalnya 503 lalu 200.
+// AssertExpectations memeriksa bahwa route dipanggil tepat n kali.
+func (r *Route) Times(n int) *Route {
+	r.times = n
+	return r
+}
+
+// Optional membuat AssertExpectations tidak menggagalkan tes jika route tidak dipanggil.
+func (r *Route) Optional() *Route {
+	r.optional = true
+	return r
+}
+
+// Calls mengembalikan permintaan yang diterima route ini.
+func (r *Route) Calls() []Request {
+	r.server.mu.Lock()
+	defer r.server.mu.Unlock()
+	return append([]Request(nil), r.calls...)
+}
+
+// methodName mengembalikan method untuk pesan error.
+func (r *Route) methodName() string {
+	if r.method == "" {
+		return "*"
+	}
+	return r.method
+}
+
+// matches melaporkan apakah req cocok dengan route. Pemanggil wajib memegang server.mu.
+func (r *Route) matches(req Request) bool {
+	if r.method != "" && r.method != "*" && r.method != req.Method {
+		return false
+	}
+	if r.path != req.Path {
+		return false
+	}
+	return r.times < 0 || len(r.calls) < r.times
+}
+
+// respond menulis respons route ke ctx.
+func (r *Route) respond(ctx *fasthttp.RequestCtx) {
+	switch {
+	case r.hang:
+		r.server.wait(-1)
+	case r.delay > 0:
+		r.server.wait(r.delay)
+	}
+
+	if r.reset {
+		ctx.HijackSetNoResponse(true)
+		ctx.Hijack(func(c net.Conn) {
+			c.Close()
+		})
+		return
+	}
+
+	for k, values := range r.header {
+		for _, v := range values {
+			ctx.Response.Header.Add(k, v)
+		}
+	}
+	if r.handler != nil {
+		r.handler(ctx)
+		return
+	}
+	ctx.SetStatusCode(r.status)
+	ctx.SetBody(r.body)
+}
@@ -4,17 +4,21 @@ go 1.24.4
 
 require github.com/valyala/fasthttp v1.62.0
 
+require gopkg.in/yaml.v3 v3.0.1
+
 require github.com/jmoiron/sqlx v1.4.0
 
 require (
 	github.com/go-playground/validator v9.31.0+incompatible
 	golang.org/x/crypto v0.39.0
+	golang.org/x/net v0.40.0
 )
 
 require (
 	github.com/go-playground/locales v0.14.1 // indirect
 	github.com/go-playground/universal-translator v0.18.1 // indirect
 	github.com/leodido/go-urn v1.4.0 // indirect
+	golang.org/x/text v0.26.0 // indirect
 	gopkg.in/go-playground/assert.v1 v1.2.1 // indirect
 )
 
@@ -25,5 +29,4 @@ require (
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/stretchr/testify v1.10.0
 	github.com/valyala/bytebufferpool v1.0.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
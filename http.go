@@ -1,15 +1,13 @@
 package gocommon
 
-import (
-	"time"
+import "time"
 
-	"encoding/json"
+// DefaultHTTPClient adalah instance HTTPClient yang dipakai oleh HTTPRequest, HTTPGetJSON, dan HTTPPostJSON.
+// Instance ini dapat diganti dengan NewHTTPClient jika pengaturan bawaan perlu diubah secara global.
+var DefaultHTTPClient = NewHTTPClient()
 
-	"github.com/valyala/fasthttp"
-)
-
-// HTTPRequest mengirim permintaan HTTP dengan metode, URL, header, dan body yang ditentukan,
-// serta mengharapkan respons dalam format JSON. Fungsi ini menggunakan pustaka fasthttp untuk performa tinggi.
+// HTTPRequest mengirim permintaan HTTP dengan metode, URL, header, dan body yang ditentukan.
+// Fungsi ini menggunakan DefaultHTTPClient yang dibangun di atas pustaka fasthttp untuk performa tinggi.
 //
 // Parameter:
 //   - method: Metode HTTP (misal: "GET", "POST").
@@ -21,39 +19,12 @@ import (
 // Return:
 //   - []byte: Body respons dalam bentuk byte slice.
 //   - int: Kode status HTTP dari respons.
-//   - error: Error jika permintaan gagal atau respons bukan JSON valid.
+//   - error: Error jika permintaan gagal.
 //
-// Fungsi ini memvalidasi bahwa body respons adalah JSON yang valid. Jika tidak, akan mengembalikan error.
+// Body respons dikembalikan apa adanya tanpa validasi. Untuk memastikan respons adalah JSON yang valid,
+// gunakan klien dengan WithStrictJSON atau opsi WithJSONValidation(true) pada helper yang menerima RequestOption.
 func HTTPRequest(method, url string, headers map[string]string, body []byte, timeout ...int) ([]byte, int, error) {
-	req := fasthttp.AcquireRequest()
-	defer fasthttp.ReleaseRequest(req)
-	resp := fasthttp.AcquireResponse()
-	defer fasthttp.ReleaseResponse(resp)
-
-	req.SetRequestURI(url)
-	req.Header.SetMethod(method)
-	for k, v := range headers {
-		req.Header.Set(k, v)
-	}
-	if body != nil {
-		req.SetBody(body)
-	}
-
-	// Default timeout: 10 seconds
-	to := 10 * 1000 // milliseconds
-	if len(timeout) > 0 {
-		to = timeout[0]
-	}
-
-	err := fasthttp.DoTimeout(req, resp, time.Duration(to)*time.Millisecond)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	responseBody := make([]byte, len(resp.Body()))
-	copy(responseBody, resp.Body())
-
-	return responseBody, resp.StatusCode(), nil
+	return DefaultHTTPClient.Request(method, url, headers, body, timeout...)
 }
 
 // HTTPGetJSON mengirim permintaan HTTP GET ke URL yang ditentukan dengan header yang diberikan,
@@ -67,49 +38,48 @@ func HTTPRequest(method, url string, headers map[string]string, body []byte, tim
 //	    // tangani error
 //	}
 //	fmt.Printf("Status: %d, Body: %s\n", status, string(resp))
-func HTTPGetJSON(url string, headers map[string]string) ([]byte, int, error) {
-	if headers == nil {
-		headers = make(map[string]string)
-	}
-	headers["Accept"] = "application/json"
-	resp, status, err := HTTPRequest("GET", url, headers, nil)
-	if err != nil {
-		return nil, status, err
-	}
-
-	return resp, status, err
+//
+// Opsi per permintaan seperti WithRetry dapat ditambahkan di akhir:
+//
+//	resp, status, err := HTTPGetJSON(url, nil, WithRetry(RetryPolicy{MaxAttempts: 3}))
+func HTTPGetJSON(url string, headers map[string]string, opts ...RequestOption) ([]byte, int, error) {
+	return DefaultHTTPClient.GetJSON(url, headers, opts...)
 }
 
 // HTTPPostJSON mengirim permintaan HTTP POST dengan body yang dienkode dalam format JSON ke URL yang ditentukan.
 // Fungsi ini mengatur header "Content-Type" dan "Accept" menjadi "application/json".
-// Body yang diberikan akan di-marshal ke JSON, permintaan dikirim, dan respons divalidasi agar merupakan JSON yang valid.
+// Body yang diberikan akan di-marshal ke JSON lalu permintaan dikirim. Respons hanya divalidasi sebagai JSON
+// jika mode ketat aktif (WithStrictJSON pada klien).
 // Fungsi mengembalikan body respons sebagai byte slice, kode status HTTP, dan error jika terjadi kesalahan.
 //
 // Parameter:
 //   - url: Endpoint tujuan permintaan POST.
 //   - headers: Header HTTP opsional yang akan disertakan dalam permintaan. Jika nil, akan dibuat map baru.
 //   - body: Data yang akan dienkode ke JSON dan dikirim sebagai body permintaan.
+//   - timeout: Timeout opsional dalam milidetik. Jika tidak diberikan, timeout DefaultHTTPClient dipakai.
 //
 // Return:
 //   - []byte: Body respons.
 //   - int: Kode status HTTP.
-//   - error: Error jika permintaan gagal, body gagal di-marshal, atau respons bukan JSON valid.
+//   - error: Error jika permintaan gagal, body gagal di-marshal, atau respons bukan JSON valid pada mode ketat.
+//
+// Gunakan HTTPPostJSONWithOptions untuk opsi per permintaan seperti WithRetry atau WithIdempotencyKey.
 func HTTPPostJSON(url string, headers map[string]string, body interface{}, timeout ...int) ([]byte, int, error) {
-	if headers == nil {
-		headers = make(map[string]string)
-	}
-	headers["Content-Type"] = "application/json"
-	headers["Accept"] = "application/json"
-
-	jsonBody, err := json.Marshal(body)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	resp, status, err := HTTPRequest("POST", url, headers, jsonBody, timeout...)
-	if err != nil {
-		return nil, status, err
+	var opts []RequestOption
+	if len(timeout) > 0 {
+		opts = append(opts, WithRequestTimeout(time.Duration(timeout[0])*time.Millisecond))
 	}
+	return DefaultHTTPClient.PostJSON(url, headers, body, opts...)
+}
 
-	return resp, status, nil
+// HTTPPostJSONWithOptions sama dengan HTTPPostJSON, tetapi menerima opsi per permintaan, misalnya
+// WithRequestTimeout, WithRetry, WithIdempotencyKey, atau WithJSONValidation(true).
+// POST hanya di-retry jika idempotency key diberikan.
+//
+// Contoh penggunaan:
+//
+//	resp, status, err := HTTPPostJSONWithOptions(url, nil, body,
+//	    WithRetry(RetryPolicy{MaxAttempts: 3}), WithIdempotencyKey(trxID))
+func HTTPPostJSONWithOptions(url string, headers map[string]string, body interface{}, opts ...RequestOption) ([]byte, int, error) {
+	return DefaultHTTPClient.PostJSON(url, headers, body, opts...)
 }
@@ -0,0 +1,266 @@
+package gocommon
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen dikembalikan (dibungkus CircuitOpenError) jika permintaan ditolak karena
+// circuit breaker untuk host tujuan sedang terbuka. Gunakan errors.Is untuk memeriksanya.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+const (
+	defaultBreakerFailureThreshold = 5
+	defaultBreakerCooldown         = 30 * time.Second
+)
+
+// CircuitState adalah status circuit breaker untuk satu host.
+type CircuitState int
+
+const (
+	// CircuitClosed berarti permintaan diteruskan seperti biasa.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen berarti permintaan langsung ditolak dengan ErrCircuitOpen sampai cooldown selesai.
+	CircuitOpen
+	// CircuitHalfOpen berarti sejumlah kecil permintaan percobaan diizinkan untuk menguji pemulihan host.
+	CircuitHalfOpen
+)
+
+// String mengembalikan nama status dalam huruf kecil, misalnya "closed".
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("CircuitState(%d)", int(s))
+}
+
+// CircuitOpenError adalah error bertipe yang dikembalikan saat circuit breaker menolak permintaan.
+// Error ini cocok dengan ErrCircuitOpen melalui errors.Is.
+type CircuitOpenError struct {
+	// Host adalah host tujuan yang circuit-nya terbuka.
+	Host string
+	// Until adalah perkiraan waktu circuit berpindah ke half-open.
+	Until time.Time
+}
+
+// Error mengimplementasikan interface error.
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%s for host %s", ErrCircuitOpen.Error(), e.Host)
+}
+
+// Is membuat errors.Is(err, ErrCircuitOpen) bernilai true.
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// CircuitBreakerConfig berisi pengaturan CircuitBreaker.
+type CircuitBreakerConfig struct {
+	// FailureThreshold adalah jumlah kegagalan berturut-turut yang membuka circuit. Default 5.
+	FailureThreshold int
+	// Cooldown adalah lama circuit tetap terbuka sebelum berpindah ke half-open. Default 30 detik.
+	Cooldown time.Duration
+	// HalfOpenMaxRequests adalah jumlah permintaan percobaan yang diizinkan saat half-open,
+	// sekaligus jumlah keberhasilan yang dibutuhkan untuk menutup circuit kembali. Default 1.
+	HalfOpenMaxRequests int
+	// IsFailure menentukan apakah hasil sebuah permintaan dihitung sebagai kegagalan.
+	// Default: error jaringan atau kode status 5xx.
+	IsFailure func(status int, err error) bool
+	// OnStateChange dipanggil setiap kali status circuit untuk suatu host berubah, berguna untuk alerting.
+	OnStateChange func(host string, from, to CircuitState)
+}
+
+// CircuitBreaker melacak kesehatan setiap host tujuan dan menolak permintaan ke host yang sedang
+// bermasalah agar goroutine tidak menunggu timeout berulang kali. Satu CircuitBreaker dapat dipakai
+// bersama oleh beberapa HTTPClient.
+type CircuitBreaker struct {
+	cfg CircuitBreakerConfig
+
+	mu    sync.Mutex
+	hosts map[string]*hostCircuit
+}
+
+// hostCircuit menyimpan status circuit untuk satu host.
+type hostCircuit struct {
+	state     CircuitState
+	failures  int
+	successes int
+	inFlight  int
+	openedAt  time.Time
+}
+
+// NewCircuitBreaker membuat CircuitBreaker baru. Nilai nol pada cfg diganti dengan nilai default.
+//
+// Contoh penggunaan:
+//
+//	breaker := NewCircuitBreaker(CircuitBreakerConfig{
+//	    FailureThreshold: 3,
+//	    Cooldown:         10 * time.Second,
+//	    OnStateChange: func(host string, from, to CircuitState) {
+//	        log.Printf("circuit %s: %s -> %s", host, from, to)
+//	    },
+//	})
+//	client := NewHTTPClient(WithCircuitBreaker(breaker))
+func NewCircuitBreaker(cfg CircuitBreakerConfig) *CircuitBreaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = defaultBreakerFailureThreshold
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = defaultBreakerCooldown
+	}
+	if cfg.HalfOpenMaxRequests <= 0 {
+		cfg.HalfOpenMaxRequests = 1
+	}
+	if cfg.IsFailure == nil {
+		cfg.IsFailure = func(status int, err error) bool {
+			return err != nil || status >= 500
+		}
+	}
+	return &CircuitBreaker{
+		cfg:   cfg,
+		hosts: make(map[string]*hostCircuit),
+	}
+}
+
+// WithCircuitBreaker memasang circuit breaker pada klien. Status dilacak per host tujuan.
+func WithCircuitBreaker(breaker *CircuitBreaker) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.breaker = breaker
+	}
+}
+
+// State mengembalikan status circuit saat ini untuk host.
+func (b *CircuitBreaker) State(host string) CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	hc, ok := b.hosts[host]
+	if !ok {
+		return CircuitClosed
+	}
+	if hc.state == CircuitOpen && time.Since(hc.openedAt) >= b.cfg.Cooldown {
+		return CircuitHalfOpen
+	}
+	return hc.state
+}
+
+// Reset mengembalikan circuit untuk host ke status closed.
+func (b *CircuitBreaker) Reset(host string) {
+	b.mu.Lock()
+	hc, ok := b.hosts[host]
+	var from CircuitState
+	if ok {
+		from = hc.state
+		delete(b.hosts, host)
+	}
+	b.mu.Unlock()
+
+	if ok && from != CircuitClosed {
+		b.notify(host, from, CircuitClosed)
+	}
+}
+
+// allow memeriksa apakah permintaan ke host boleh dikirim. Jika boleh, pemanggil wajib
+// melaporkan hasilnya melalui record.
+func (b *CircuitBreaker) allow(host string) error {
+	b.mu.Lock()
+	hc, ok := b.hosts[host]
+	if !ok {
+		hc = &hostCircuit{}
+		b.hosts[host] = hc
+	}
+
+	var changed bool
+	if hc.state == CircuitOpen {
+		if time.Since(hc.openedAt) < b.cfg.Cooldown {
+			until := hc.openedAt.Add(b.cfg.Cooldown)
+			b.mu.Unlock()
+			return &CircuitOpenError{Host: host, Until: until}
+		}
+		hc.state = CircuitHalfOpen
+		hc.successes = 0
+		hc.inFlight = 0
+		changed = true
+	}
+	if hc.state == CircuitHalfOpen {
+		if hc.inFlight >= b.cfg.HalfOpenMaxRequests {
+			b.mu.Unlock()
+			return &CircuitOpenError{Host: host}
+		}
+		hc.inFlight++
+	}
+	b.mu.Unlock()
+
+	if changed {
+		b.notify(host, CircuitOpen, CircuitHalfOpen)
+	}
+	return nil
+}
+
+// record mencatat hasil permintaan ke host dan memperbarui status circuit.
+func (b *CircuitBreaker) record(host string, status int, err error) {
+	failed := b.cfg.IsFailure(status, err)
+
+	b.mu.Lock()
+	hc, ok := b.hosts[host]
+	if !ok {
+		b.mu.Unlock()
+		return
+	}
+
+	from := hc.state
+	switch hc.state {
+	case CircuitClosed:
+		if !failed {
+			hc.failures = 0
+			break
+		}
+		hc.failures++
+		if hc.failures >= b.cfg.FailureThreshold {
+			hc.state = CircuitOpen
+			hc.openedAt = time.Now()
+		}
+	case CircuitHalfOpen:
+		hc.inFlight--
+		if failed {
+			hc.state = CircuitOpen
+			hc.openedAt = time.Now()
+			break
+		}
+		hc.successes++
+		if hc.successes >= b.cfg.HalfOpenMaxRequests {
+			hc.state = CircuitClosed
+			hc.failures = 0
+		}
+	}
+	to := hc.state
+	b.mu.Unlock()
+
+	if from != to {
+		b.notify(host, from, to)
+	}
+}
+
+// release melepas slot permintaan percobaan tanpa mencatat hasil, misalnya saat permintaan
+// dibatalkan oleh pemanggil sehingga tidak mencerminkan kesehatan host.
+func (b *CircuitBreaker) release(host string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if hc, ok := b.hosts[host]; ok && hc.state == CircuitHalfOpen && hc.inFlight > 0 {
+		hc.inFlight--
+	}
+}
+
+// notify memanggil callback OnStateChange jika diatur.
+func (b *CircuitBreaker) notify(host string, from, to CircuitState) {
+	if b.cfg.OnStateChange != nil {
+		b.cfg.OnStateChange(host, from, to)
+	}
+}
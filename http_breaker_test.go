@@ -0,0 +1,108 @@
+package gocommon
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestCircuitBreaker_OpensAndRecovers(t *testing.T) {
+	var healthy atomic.Bool
+	var calls int32
+	handler := func(ctx *fasthttp.RequestCtx) {
+		atomic.AddInt32(&calls, 1)
+		if !healthy.Load() {
+			ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
+			return
+		}
+		ctx.SetStatusCode(fasthttp.StatusOK)
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	var mu sync.Mutex
+	var transitions []string
+	breaker := NewCircuitBreaker(CircuitBreakerConfig{
+		FailureThreshold: 2,
+		Cooldown:         50 * time.Millisecond,
+		OnStateChange: func(host string, from, to CircuitState) {
+			mu.Lock()
+			transitions = append(transitions, from.String()+"->"+to.String())
+			mu.Unlock()
+		},
+	})
+	client := NewHTTPClient(WithCircuitBreaker(breaker))
+	host := strings.TrimPrefix(addr, "http://")
+
+	for i := 0; i < 2; i++ {
+		_, status, err := client.GetJSON(addr, nil)
+		require.NoError(t, err)
+		require.Equal(t, fasthttp.StatusServiceUnavailable, status)
+	}
+	require.Equal(t, CircuitOpen, breaker.State(host))
+
+	_, _, err = client.GetJSON(addr, nil)
+	require.True(t, errors.Is(err, ErrCircuitOpen))
+	var openErr *CircuitOpenError
+	require.True(t, errors.As(err, &openErr))
+	require.Equal(t, host, openErr.Host)
+	require.EqualValues(t, 2, atomic.LoadInt32(&calls))
+
+	time.Sleep(60 * time.Millisecond)
+	require.Equal(t, CircuitHalfOpen, breaker.State(host))
+
+	healthy.Store(true)
+	_, status, err := client.GetJSON(addr, nil)
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusOK, status)
+	require.Equal(t, CircuitClosed, breaker.State(host))
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, []string{"closed->open", "open->half-open", "half-open->closed"}, transitions)
+}
+
+func TestCircuitBreaker_HalfOpenFailureReopens(t *testing.T) {
+	breaker := NewCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1, Cooldown: 10 * time.Millisecond})
+
+	require.NoError(t, breaker.allow("partner"))
+	breaker.record("partner", 0, errors.New("connection refused"))
+	require.Equal(t, CircuitOpen, breaker.State("partner"))
+
+	time.Sleep(15 * time.Millisecond)
+	require.NoError(t, breaker.allow("partner"))
+	// Only one probe is allowed while half-open.
+	require.ErrorIs(t, breaker.allow("partner"), ErrCircuitOpen)
+
+	breaker.record("partner", fasthttp.StatusInternalServerError, nil)
+	require.Equal(t, CircuitOpen, breaker.State("partner"))
+
+	breaker.Reset("partner")
+	require.Equal(t, CircuitClosed, breaker.State("partner"))
+}
+
+func TestCircuitBreaker_StopsRetries(t *testing.T) {
+	var calls int32
+	handler := func(ctx *fasthttp.RequestCtx) {
+		atomic.AddInt32(&calls, 1)
+		ctx.SetStatusCode(fasthttp.StatusBadGateway)
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	client := NewHTTPClient(
+		WithCircuitBreaker(NewCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 2})),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond}),
+	)
+	_, _, err = client.GetJSON(addr, nil)
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	require.EqualValues(t, 2, atomic.LoadInt32(&calls))
+}
@@ -0,0 +1,357 @@
+package gocommon
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// CacheStatusHeader adalah header yang ditambahkan HTTPCache pada respons dari cache: "HIT" jika
+// respons masih segar, atau "REVALIDATED" jika server membalas 304 Not Modified.
+const CacheStatusHeader = "X-Cache"
+
+// defaultCacheCapacity adalah kapasitas LRUCacheStore jika HTTPCacheConfig.Store nil.
+const defaultCacheCapacity = 1000
+
+// CachedResponse adalah respons yang tersimpan di cache.
+type CachedResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	// StoredAt adalah waktu respons diterima atau terakhir divalidasi ulang.
+	StoredAt time.Time
+	// Expires adalah batas kesegaran respons; setelahnya respons harus divalidasi ulang.
+	Expires time.Time
+	// Vary berisi hash nilai header permintaan yang disebut pada header Vary respons, beserta Authorization.
+	Vary map[string]string
+}
+
+// CacheStore adalah penyimpanan respons HTTPCache. Implementasi harus aman digunakan secara konkuren.
+// Nilai *CachedResponse diperlakukan sebagai read-only setelah disimpan.
+type CacheStore interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, res *CachedResponse)
+	Delete(key string)
+}
+
+// LRUCacheStore adalah CacheStore di memori yang membuang entri paling lama tidak dipakai
+// ketika kapasitasnya penuh.
+type LRUCacheStore struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+// lruEntry adalah elemen list pada LRUCacheStore.
+type lruEntry struct {
+	key string
+	res *CachedResponse
+}
+
+// NewLRUCacheStore membuat LRUCacheStore dengan kapasitas maksimum capacity entri.
+// Kapasitas <= 0 memakai default 1000.
+func NewLRUCacheStore(capacity int) *LRUCacheStore {
+	if capacity <= 0 {
+		capacity = defaultCacheCapacity
+	}
+	return &LRUCacheStore{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// Get mengembalikan entri untuk key dan menandainya sebagai baru dipakai.
+func (s *LRUCacheStore) Get(key string) (*CachedResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	el, ok := s.items[key]
+	if !ok {
+		return nil, false
+	}
+	s.ll.MoveToFront(el)
+	return el.Value.(*lruEntry).res, true
+}
+
+// Set menyimpan entri untuk key, membuang entri paling lama jika kapasitas terlampaui.
+func (s *LRUCacheStore) Set(key string, res *CachedResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if el, ok := s.items[key]; ok {
+		el.Value.(*lruEntry).res = res
+		s.ll.MoveToFront(el)
+		return
+	}
+	s.items[key] = s.ll.PushFront(&lruEntry{key: key, res: res})
+	for s.ll.Len() > s.capacity {
+		oldest := s.ll.Back()
+		s.ll.Remove(oldest)
+		delete(s.items, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// Delete menghapus entri untuk key.
+func (s *LRUCacheStore) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if el, ok := s.items[key]; ok {
+		s.ll.Remove(el)
+		delete(s.items, key)
+	}
+}
+
+// Len mengembalikan jumlah entri di cache.
+func (s *LRUCacheStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ll.Len()
+}
+
+// HTTPCacheConfig berisi pengaturan HTTPCache.
+type HTTPCacheConfig struct {
+	// Store adalah penyimpanan respons. Default NewLRUCacheStore(1000).
+	Store CacheStore
+}
+
+// HTTPCache adalah cache respons GET di sisi klien (private cache) yang mengikuti Cache-Control:
+//   - respons 200 disimpan selama max-age (dikurangi header Age), atau sampai header Expires;
+//   - respons dengan no-store tidak disimpan, dan no-cache selalu divalidasi ulang;
+//   - respons tanpa masa segar tetapi memiliki ETag atau Last-Modified disimpan dan selalu divalidasi ulang;
+//   - respons kedaluwarsa divalidasi ulang dengan If-None-Match/If-Modified-Since, dan balasan
+//     304 Not Modified diganti dengan respons dari cache.
+//
+// Header Vary dan Authorization permintaan ikut dicocokkan sehingga respons milik satu token
+// tidak diberikan ke token lain. Permintaan dengan Cache-Control: no-store tidak memakai cache,
+// sedangkan no-cache memaksa validasi ulang.
+//
+// Contoh penggunaan:
+//
+//	cache := NewHTTPCache(HTTPCacheConfig{Store: NewLRUCacheStore(500)})
+//	client := NewHTTPClient(WithHTTPCache(cache))
+//	banks, status, err := client.GetJSON("https://api.partner.co.id/v1/banks", nil)
+type HTTPCache struct {
+	store CacheStore
+	now   func() time.Time
+}
+
+// NewHTTPCache membuat HTTPCache baru.
+func NewHTTPCache(cfg HTTPCacheConfig) *HTTPCache {
+	if cfg.Store == nil {
+		cfg.Store = NewLRUCacheStore(defaultCacheCapacity)
+	}
+	return &HTTPCache{store: cfg.Store, now: time.Now}
+}
+
+// WithHTTPCache memasang cache pada klien. Karena respons dari cache tidak melewati middleware yang
+// didaftarkan setelahnya, pasang opsi ini sebelum middleware lain jika semua permintaan ingin dicatat.
+func WithHTTPCache(cache *HTTPCache) HTTPClientOption {
+	return WithMiddleware(cache.Middleware())
+}
+
+// Middleware mengembalikan HTTPMiddleware yang menerapkan cache pada permintaan GET.
+func (c *HTTPCache) Middleware() HTTPMiddleware {
+	return func(next HTTPHandler) HTTPHandler {
+		return func(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+			if !req.Header.IsGet() || req.IsBodyStream() {
+				return next(ctx, req, resp)
+			}
+			reqCC := parseCacheControl(string(req.Header.Peek("Cache-Control")))
+			if _, ok := reqCC["no-store"]; ok {
+				return next(ctx, req, resp)
+			}
+			_, noCache := reqCC["no-cache"]
+
+			key := req.URI().String()
+			cached, ok := c.store.Get(key)
+			if ok && !cached.matchesVary(req) {
+				cached, ok = nil, false
+			}
+			if ok && !noCache && c.now().Before(cached.Expires) {
+				cached.writeTo(resp, "HIT")
+				return nil
+			}
+
+			// Validasi ulang hanya dilakukan jika pemanggil tidak mengirim header kondisional sendiri.
+			ifNoneMatch := string(req.Header.Peek("If-None-Match"))
+			ifModifiedSince := string(req.Header.Peek("If-Modified-Since"))
+			conditional := false
+			if ok && ifNoneMatch == "" && ifModifiedSince == "" {
+				if etag := cached.Header.Get("ETag"); etag != "" {
+					req.Header.Set("If-None-Match", etag)
+					conditional = true
+				}
+				if lastModified := cached.Header.Get("Last-Modified"); lastModified != "" {
+					req.Header.Set("If-Modified-Since", lastModified)
+					conditional = true
+				}
+			}
+
+			err := next(ctx, req, resp)
+			restoreHeader(req, "If-None-Match", ifNoneMatch)
+			restoreHeader(req, "If-Modified-Since", ifModifiedSince)
+			if err != nil || resp.BodyStream() != nil {
+				return err
+			}
+
+			now := c.now()
+			switch status := resp.StatusCode(); {
+			case status == fasthttp.StatusNotModified && conditional:
+				updated := cached.revalidated(resp, now)
+				c.store.Set(key, updated)
+				updated.writeTo(resp, "REVALIDATED")
+			case status == fasthttp.StatusOK:
+				if entry := newCachedResponse(req, resp, now); entry != nil {
+					c.store.Set(key, entry)
+				} else if ok {
+					c.store.Delete(key)
+				}
+			}
+			return nil
+		}
+	}
+}
+
+// newCachedResponse membangun entri cache dari resp, atau nil jika respons tidak boleh disimpan.
+func newCachedResponse(req *fasthttp.Request, resp *fasthttp.Response, now time.Time) *CachedResponse {
+	header := responseHeader(resp)
+	cc := parseCacheControl(header.Get("Cache-Control"))
+	if _, ok := cc["no-store"]; ok {
+		return nil
+	}
+	vary, ok := varyValues(req, header.Get("Vary"))
+	if !ok {
+		return nil
+	}
+	expires, hasFreshness := freshnessExpiry(header, cc, now)
+	if !hasFreshness && header.Get("ETag") == "" && header.Get("Last-Modified") == "" {
+		return nil
+	}
+	return &CachedResponse{
+		StatusCode: resp.StatusCode(),
+		Header:     header,
+		Body:       append([]byte(nil), resp.Body()...),
+		StoredAt:   now,
+		Expires:    expires,
+		Vary:       vary,
+	}
+}
+
+// revalidated mengembalikan salinan entri dengan header dan masa segar dari respons 304.
+func (r *CachedResponse) revalidated(resp *fasthttp.Response, now time.Time) *CachedResponse {
+	header := r.Header.Clone()
+	for k, values := range responseHeader(resp) {
+		switch http.CanonicalHeaderKey(k) {
+		case "Content-Length", "Content-Encoding", "Content-Type", "Transfer-Encoding":
+			continue
+		}
+		header[k] = values
+	}
+	updated := *r
+	updated.Header = header
+	updated.StoredAt = now
+	updated.Expires, _ = freshnessExpiry(header, parseCacheControl(header.Get("Cache-Control")), now)
+	return &updated
+}
+
+// writeTo menulis entri ke resp beserta header CacheStatusHeader.
+func (r *CachedResponse) writeTo(resp *fasthttp.Response, status string) {
+	resp.Reset()
+	resp.SetStatusCode(r.StatusCode)
+	for k, values := range r.Header {
+		if http.CanonicalHeaderKey(k) == "Content-Length" {
+			continue
+		}
+		for _, v := range values {
+			resp.Header.Add(k, v)
+		}
+	}
+	resp.Header.Set(CacheStatusHeader, status)
+	resp.SetBody(r.Body)
+}
+
+// matchesVary melaporkan apakah header permintaan sama dengan saat respons disimpan.
+func (r *CachedResponse) matchesVary(req *fasthttp.Request) bool {
+	for name, hash := range r.Vary {
+		if hashHeaderValue(req, name) != hash {
+			return false
+		}
+	}
+	return true
+}
+
+// varyValues mengumpulkan hash header permintaan yang disebut pada Vary, ditambah Authorization.
+// Nilai false berarti respons tidak dapat disimpan (Vary: *).
+func varyValues(req *fasthttp.Request, vary string) (map[string]string, bool) {
+	values := map[string]string{"Authorization": hashHeaderValue(req, "Authorization")}
+	for _, name := range strings.Split(vary, ",") {
+		name = strings.TrimSpace(name)
+		switch name {
+		case "":
+			continue
+		case "*":
+			return nil, false
+		}
+		values[http.CanonicalHeaderKey(name)] = hashHeaderValue(req, name)
+	}
+	return values, true
+}
+
+// hashHeaderValue mengembalikan hash SHA-256 nilai header agar token tidak tersimpan di cache.
+func hashHeaderValue(req *fasthttp.Request, name string) string {
+	v := req.Header.Peek(name)
+	if len(v) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256(v)
+	return hex.EncodeToString(sum[:])
+}
+
+// freshnessExpiry menghitung batas kesegaran dari max-age, Age, dan Expires. Nilai false berarti
+// respons tidak memiliki masa segar eksplisit sehingga harus divalidasi ulang setiap dipakai.
+func freshnessExpiry(header http.Header, cc map[string]string, now time.Time) (time.Time, bool) {
+	if _, ok := cc["no-cache"]; ok {
+		return now, false
+	}
+	if v, ok := cc["max-age"]; ok {
+		maxAge, err := strconv.Atoi(v)
+		if err != nil {
+			return now, false
+		}
+		age, _ := strconv.Atoi(header.Get("Age"))
+		return now.Add(time.Duration(maxAge-age) * time.Second), true
+	}
+	if v := header.Get("Expires"); v != "" {
+		expires, err := http.ParseTime(v)
+		if err != nil {
+			return now, false
+		}
+		if date, err := http.ParseTime(header.Get("Date")); err == nil {
+			return now.Add(expires.Sub(date)), true
+		}
+		return expires, true
+	}
+	return now, false
+}
+
+// parseCacheControl mengurai header Cache-Control menjadi map direktif (huruf kecil) ke nilainya.
+func parseCacheControl(value string) map[string]string {
+	cc := make(map[string]string)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, val, _ := strings.Cut(part, "=")
+		cc[strings.ToLower(strings.TrimSpace(name))] = strings.Trim(strings.TrimSpace(val), `"`)
+	}
+	return cc
+}
@@ -0,0 +1,193 @@
+package gocommon
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestHTTPCache_MaxAgeAndNoStore(t *testing.T) {
+	var mu sync.Mutex
+	hits := map[string]int{}
+	handler := func(ctx *fasthttp.RequestCtx) {
+		mu.Lock()
+		hits[string(ctx.Path())]++
+		mu.Unlock()
+		switch string(ctx.Path()) {
+		case "/banks":
+			ctx.Response.Header.Set("Cache-Control", "public, max-age=60")
+		case "/aged":
+			ctx.Response.Header.Set("Cache-Control", "max-age=60")
+			ctx.Response.Header.Set("Age", "50")
+		case "/secret":
+			ctx.Response.Header.Set("Cache-Control", "no-store, max-age=60")
+		}
+		ctx.SetContentType("application/json")
+		ctx.SetBodyString(`{"path":"` + string(ctx.Path()) + `"}`)
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	now := time.Now()
+	cache := NewHTTPCache(HTTPCacheConfig{})
+	cache.now = func() time.Time { return now }
+	client := NewHTTPClient(WithBaseURL(addr), WithHTTPCache(cache))
+
+	for i := 0; i < 3; i++ {
+		resp, status, err := client.GetJSON("/banks", nil)
+		require.NoError(t, err)
+		require.Equal(t, fasthttp.StatusOK, status)
+		require.JSONEq(t, `{"path":"/banks"}`, string(resp))
+	}
+	require.Equal(t, 1, hits["/banks"])
+
+	res, err := client.execute(t.Context(), "GET", "/banks", nil, nil, client.newRequestOptions(nil))
+	require.NoError(t, err)
+	require.Equal(t, "HIT", res.header.Get(CacheStatusHeader))
+
+	// Permintaan POST dan permintaan dengan no-store tidak memakai cache.
+	_, _, err = client.PostJSON("/banks", nil, nil)
+	require.NoError(t, err)
+	_, _, err = client.GetJSON("/banks", map[string]string{"Cache-Control": "no-store"})
+	require.NoError(t, err)
+	require.Equal(t, 3, hits["/banks"])
+
+	now = now.Add(61 * time.Second)
+	_, _, err = client.GetJSON("/banks", nil)
+	require.NoError(t, err)
+	require.Equal(t, 4, hits["/banks"])
+
+	// Age mengurangi masa segar: sisa 10 detik.
+	_, _, err = client.GetJSON("/aged", nil)
+	require.NoError(t, err)
+	now = now.Add(11 * time.Second)
+	_, _, err = client.GetJSON("/aged", nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, hits["/aged"])
+
+	for i := 0; i < 2; i++ {
+		_, _, err = client.GetJSON("/secret", nil)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 2, hits["/secret"])
+}
+
+func TestHTTPCache_Revalidation(t *testing.T) {
+	const lastModified = "Wed, 01 Jan 2025 00:00:00 GMT"
+	var mu sync.Mutex
+	var full, notModified int
+	var gotIfNoneMatch, gotIfModifiedSince []string
+	handler := func(ctx *fasthttp.RequestCtx) {
+		mu.Lock()
+		defer mu.Unlock()
+		gotIfNoneMatch = append(gotIfNoneMatch, string(ctx.Request.Header.Peek("If-None-Match")))
+		gotIfModifiedSince = append(gotIfModifiedSince, string(ctx.Request.Header.Peek("If-Modified-Since")))
+		if string(ctx.Request.Header.Peek("If-None-Match")) == `"v1"` {
+			notModified++
+			ctx.Response.Header.Set("Cache-Control", "max-age=30")
+			ctx.SetStatusCode(fasthttp.StatusNotModified)
+			return
+		}
+		full++
+		ctx.Response.Header.Set("ETag", `"v1"`)
+		ctx.Response.Header.Set("Last-Modified", lastModified)
+		ctx.Response.Header.Set("Cache-Control", "no-cache")
+		ctx.SetContentType("application/json")
+		ctx.SetBodyString(`{"regions":["jakarta","bandung"]}`)
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	now := time.Now()
+	cache := NewHTTPCache(HTTPCacheConfig{})
+	cache.now = func() time.Time { return now }
+	client := NewHTTPClient(WithBaseURL(addr), WithHTTPCache(cache))
+
+	resp, status, err := client.GetJSON("/regions", nil)
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusOK, status)
+	require.JSONEq(t, `{"regions":["jakarta","bandung"]}`, string(resp))
+
+	// no-cache: selalu divalidasi ulang, 304 diganti respons dari cache.
+	res, err := client.execute(t.Context(), "GET", "/regions", nil, nil, client.newRequestOptions(nil))
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusOK, res.status)
+	require.Equal(t, "REVALIDATED", res.header.Get(CacheStatusHeader))
+	require.JSONEq(t, `{"regions":["jakarta","bandung"]}`, string(res.body))
+	require.Equal(t, []string{"", `"v1"`}, gotIfNoneMatch)
+	require.Equal(t, []string{"", lastModified}, gotIfModifiedSince)
+
+	// Balasan 304 membawa max-age=30, sehingga permintaan berikutnya langsung dari cache.
+	_, _, err = client.GetJSON("/regions", nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, full)
+	require.Equal(t, 1, notModified)
+
+	// Cache-Control: no-cache pada permintaan memaksa validasi ulang.
+	_, _, err = client.GetJSON("/regions", map[string]string{"Cache-Control": "no-cache"})
+	require.NoError(t, err)
+	require.Equal(t, 2, notModified)
+
+	// Header kondisional milik pemanggil diteruskan apa adanya.
+	_, status, err = client.GetJSON("/regions", map[string]string{"If-None-Match": `"v0"`, "Cache-Control": "no-cache"})
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusOK, status)
+	require.Equal(t, 2, full)
+}
+
+func TestHTTPCache_VaryAndAuthorization(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	handler := func(ctx *fasthttp.RequestCtx) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		ctx.Response.Header.Set("Cache-Control", "max-age=60")
+		ctx.Response.Header.Set("Vary", "Accept-Language")
+		ctx.SetBodyString(string(ctx.Request.Header.Peek("Authorization")) + "|" +
+			string(ctx.Request.Header.Peek("Accept-Language")))
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	client := NewHTTPClient(WithBaseURL(addr), WithHTTPCache(NewHTTPCache(HTTPCacheConfig{})))
+	get := func(auth, lang string) string {
+		resp, _, err := client.GetJSON("/profile", map[string]string{"Authorization": auth, "Accept-Language": lang})
+		require.NoError(t, err)
+		return string(resp)
+	}
+
+	require.Equal(t, "Bearer a|id", get("Bearer a", "id"))
+	require.Equal(t, "Bearer a|id", get("Bearer a", "id"))
+	require.Equal(t, 1, calls)
+	require.Equal(t, "Bearer b|id", get("Bearer b", "id"))
+	require.Equal(t, "Bearer b|en", get("Bearer b", "en"))
+	require.Equal(t, 3, calls)
+}
+
+func TestLRUCacheStore_Eviction(t *testing.T) {
+	store := NewLRUCacheStore(2)
+	store.Set("a", &CachedResponse{StatusCode: 1})
+	store.Set("b", &CachedResponse{StatusCode: 2})
+	_, ok := store.Get("a")
+	require.True(t, ok)
+	store.Set("c", &CachedResponse{StatusCode: 3})
+
+	_, ok = store.Get("b")
+	require.False(t, ok, "b is least recently used")
+	res, ok := store.Get("a")
+	require.True(t, ok)
+	require.Equal(t, 1, res.StatusCode)
+	require.Equal(t, 2, store.Len())
+
+	store.Delete("a")
+	_, ok = store.Get("a")
+	require.False(t, ok)
+	require.Equal(t, 1, store.Len())
+}
@@ -0,0 +1,387 @@
+package gocommon
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/valyala/fasthttp"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrCassetteMiss dikembalikan (dibungkus) saat mode replay tidak menemukan interaksi yang cocok
+// dengan permintaan. Gunakan errors.Is untuk memeriksanya.
+var ErrCassetteMiss = errors.New("cassette: no matching interaction")
+
+// CassetteMode menentukan apakah Cassette merekam atau memutar ulang interaksi.
+type CassetteMode int
+
+const (
+	// CassetteAuto memutar ulang jika file cassette sudah ada, dan merekam jika belum.
+	CassetteAuto CassetteMode = iota
+	// CassetteReplay hanya memutar ulang; permintaan tanpa rekaman yang cocok gagal dengan ErrCassetteMiss
+	// dan tidak pernah dikirim ke jaringan.
+	CassetteReplay
+	// CassetteRecord selalu mengirim permintaan ke server dan menimpa file cassette dengan hasilnya.
+	CassetteRecord
+)
+
+// CassetteRequest adalah permintaan yang tersimpan di cassette.
+type CassetteRequest struct {
+	Method   string      `json:"method" yaml:"method"`
+	URL      string      `json:"url" yaml:"url"`
+	Headers  http.Header `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Body     string      `json:"body,omitempty" yaml:"body,omitempty"`
+	Encoding string      `json:"encoding,omitempty" yaml:"encoding,omitempty"`
+}
+
+// CassetteResponse adalah respons yang tersimpan di cassette.
+type CassetteResponse struct {
+	StatusCode int         `json:"status_code" yaml:"status_code"`
+	Headers    http.Header `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Body       string      `json:"body,omitempty" yaml:"body,omitempty"`
+	// Encoding bernilai "base64" jika body bukan UTF-8 yang valid.
+	Encoding string `json:"encoding,omitempty" yaml:"encoding,omitempty"`
+}
+
+// CassetteInteraction adalah satu pasangan permintaan dan respons.
+type CassetteInteraction struct {
+	Request  CassetteRequest  `json:"request" yaml:"request"`
+	Response CassetteResponse `json:"response" yaml:"response"`
+}
+
+// CassetteMatcher menentukan apakah permintaan live cocok dengan permintaan yang direkam.
+// Permintaan live sudah melalui redaksi yang sama dengan rekaman.
+type CassetteMatcher func(live, recorded *CassetteRequest) bool
+
+// MatchMethod mencocokkan metode HTTP.
+func MatchMethod(live, recorded *CassetteRequest) bool {
+	return strings.EqualFold(live.Method, recorded.Method)
+}
+
+// MatchURL mencocokkan URL lengkap. Urutan parameter query diabaikan.
+func MatchURL(live, recorded *CassetteRequest) bool {
+	return normalizeCassetteURL(live.URL, true) == normalizeCassetteURL(recorded.URL, true)
+}
+
+// MatchPath mencocokkan path dan query tanpa skema dan host, berguna jika host berubah antar
+// rekaman, misalnya port server uji yang acak.
+func MatchPath(live, recorded *CassetteRequest) bool {
+	return normalizeCassetteURL(live.URL, false) == normalizeCassetteURL(recorded.URL, false)
+}
+
+// MatchBody mencocokkan body permintaan. Body JSON dibandingkan secara semantik sehingga
+// perbedaan spasi dan urutan field diabaikan.
+func MatchBody(live, recorded *CassetteRequest) bool {
+	if live.Body == recorded.Body {
+		return true
+	}
+	var a, b interface{}
+	if json.Unmarshal([]byte(live.Body), &a) != nil || json.Unmarshal([]byte(recorded.Body), &b) != nil {
+		return false
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// MatchHeaders mengembalikan matcher yang mencocokkan nilai header dengan nama yang diberikan.
+func MatchHeaders(names ...string) CassetteMatcher {
+	return func(live, recorded *CassetteRequest) bool {
+		for _, name := range names {
+			if !reflect.DeepEqual(live.Headers.Values(name), recorded.Headers.Values(name)) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// CassetteConfig berisi pengaturan Cassette.
+type CassetteConfig struct {
+	// Mode menentukan perilaku rekam/putar ulang. Default CassetteAuto.
+	Mode CassetteMode
+	// Matchers dipakai untuk mencari rekaman yang cocok. Default MatchMethod dan MatchURL.
+	Matchers []CassetteMatcher
+	// RedactHeaders adalah header permintaan dan respons yang nilainya disamarkan sebelum disimpan.
+	// Default Authorization, Proxy-Authorization, Cookie, Set-Cookie, X-Signature, dan X-Api-Key.
+	// Gunakan slice kosong (bukan nil) untuk menonaktifkan.
+	RedactHeaders []string
+	// RedactFields adalah nama field body JSON, body form, dan parameter query yang nilainya disamarkan.
+	// Default sama dengan SlogMiddleware: password, pin, otp, card_number, cvv, access_token, refresh_token,
+	// dan client_secret. Gunakan slice kosong untuk menonaktifkan.
+	RedactFields []string
+	// Redact dipanggil untuk setiap interaksi setelah redaksi bawaan, untuk kebutuhan redaksi tambahan.
+	Redact func(*CassetteInteraction)
+}
+
+// Cassette merekam interaksi HTTP ke file YAML atau JSON dan memutarnya ulang secara deterministik,
+// sehingga tes integrasi dapat berjalan tanpa jaringan. Format file ditentukan dari ekstensi:
+// ".json" untuk JSON, selain itu YAML.
+type Cassette struct {
+	path   string
+	cfg    CassetteConfig
+	replay bool
+
+	mu           sync.Mutex
+	interactions []*CassetteInteraction
+	used         []bool
+}
+
+// NewCassette membuka cassette di path. Pada mode replay (atau auto dengan file yang sudah ada)
+// interaksi dibaca dari file; pada mode record file ditimpa setiap kali interaksi baru direkam.
+//
+// Contoh penggunaan di tes:
+//
+//	cassette, err := NewCassette("testdata/partner_inquiry.yaml", CassetteConfig{
+//	    Matchers: []CassetteMatcher{MatchMethod, MatchPath, MatchBody},
+//	})
+//	require.NoError(t, err)
+//	client := NewHTTPClient(WithBaseURL(partnerURL), WithCassette(cassette))
+func NewCassette(path string, cfg CassetteConfig) (*Cassette, error) {
+	if len(cfg.Matchers) == 0 {
+		cfg.Matchers = []CassetteMatcher{MatchMethod, MatchURL}
+	}
+	if cfg.RedactHeaders == nil {
+		cfg.RedactHeaders = defaultRedactHeaders
+	}
+	if cfg.RedactFields == nil {
+		cfg.RedactFields = defaultRedactFields
+	}
+
+	c := &Cassette{path: path, cfg: cfg}
+	switch cfg.Mode {
+	case CassetteReplay:
+		c.replay = true
+	case CassetteAuto:
+		if _, err := os.Stat(path); err == nil {
+			c.replay = true
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+	if !c.replay {
+		return c, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read cassette: %w", err)
+	}
+	if c.isJSON() {
+		err = json.Unmarshal(data, &c.interactions)
+	} else {
+		err = yaml.Unmarshal(data, &c.interactions)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decode cassette %s: %w", path, err)
+	}
+	c.used = make([]bool, len(c.interactions))
+	return c, nil
+}
+
+// WithCassette memasang cassette pada klien sebagai middleware.
+func WithCassette(c *Cassette) HTTPClientOption {
+	return WithMiddleware(c.Middleware())
+}
+
+// Recording melaporkan apakah cassette sedang merekam.
+func (c *Cassette) Recording() bool {
+	return !c.replay
+}
+
+// Interactions mengembalikan salinan daftar interaksi di cassette.
+func (c *Cassette) Interactions() []CassetteInteraction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	out := make([]CassetteInteraction, len(c.interactions))
+	for i, in := range c.interactions {
+		out[i] = *in
+	}
+	return out
+}
+
+// Middleware mengembalikan HTTPMiddleware yang merekam atau memutar ulang permintaan.
+func (c *Cassette) Middleware() HTTPMiddleware {
+	return func(next HTTPHandler) HTTPHandler {
+		return func(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+			if c.replay {
+				return c.play(req, resp)
+			}
+			if err := next(ctx, req, resp); err != nil {
+				return err
+			}
+			return c.record(req, resp)
+		}
+	}
+}
+
+// Save menulis seluruh interaksi ke file cassette. Pada mode record, Save dipanggil otomatis
+// setiap kali interaksi direkam.
+func (c *Cassette) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.saveLocked()
+}
+
+// saveLocked menulis cassette ke disk. Pemanggil wajib memegang c.mu.
+func (c *Cassette) saveLocked() error {
+	var data []byte
+	var err error
+	if c.isJSON() {
+		data, err = json.MarshalIndent(c.interactions, "", "  ")
+	} else {
+		data, err = yaml.Marshal(c.interactions)
+	}
+	if err != nil {
+		return fmt.Errorf("encode cassette: %w", err)
+	}
+	if dir := filepath.Dir(c.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(c.path, data, 0o644)
+}
+
+// isJSON melaporkan apakah cassette memakai format JSON.
+func (c *Cassette) isJSON() bool {
+	return strings.EqualFold(filepath.Ext(c.path), ".json")
+}
+
+// record menyimpan interaksi req/resp yang baru saja dikirim.
+func (c *Cassette) record(req *fasthttp.Request, resp *fasthttp.Response) error {
+	// Body() membaca habis body stream (misalnya pada download) sehingga respons tetap utuh
+	// untuk pemanggil setelah direkam.
+	respBody := resp.Body()
+	in := &CassetteInteraction{
+		Request: c.captureRequest(req),
+		Response: CassetteResponse{
+			StatusCode: resp.StatusCode(),
+			Headers:    responseHeader(resp),
+		},
+	}
+	// Body terkompresi disimpan dalam bentuk asli agar dapat diredaksi dan dibaca di file cassette.
+	if len(resp.Header.ContentEncoding()) > 0 {
+		if decoded, err := resp.BodyUncompressed(); err == nil {
+			respBody = decoded
+			in.Response.Headers.Del("Content-Encoding")
+			in.Response.Headers.Del("Content-Length")
+		}
+	}
+	in.Response.Body, in.Response.Encoding = encodeCassetteBody(respBody)
+	redactHeaders(in.Response.Headers, c.cfg.RedactHeaders)
+	if in.Response.Encoding == "" {
+		in.Response.Body = redactBody(in.Response.Body, in.Response.Headers.Get("Content-Type"), c.cfg.RedactFields)
+	}
+	if c.cfg.Redact != nil {
+		c.cfg.Redact(in)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.interactions = append(c.interactions, in)
+	return c.saveLocked()
+}
+
+// play mengisi resp dari interaksi pertama yang cocok dan belum dipakai.
+func (c *Cassette) play(req *fasthttp.Request, resp *fasthttp.Response) error {
+	live := c.captureRequest(req)
+
+	c.mu.Lock()
+	var found *CassetteInteraction
+	for i, in := range c.interactions {
+		if !c.used[i] && c.matches(&live, &in.Request) {
+			c.used[i] = true
+			found = in
+			break
+		}
+	}
+	c.mu.Unlock()
+	if found == nil {
+		return fmt.Errorf("%w for %s %s", ErrCassetteMiss, live.Method, live.URL)
+	}
+
+	body, err := decodeCassetteBody(found.Response.Body, found.Response.Encoding)
+	if err != nil {
+		return fmt.Errorf("decode cassette body: %w", err)
+	}
+	resp.SetStatusCode(found.Response.StatusCode)
+	for k, values := range found.Response.Headers {
+		for _, v := range values {
+			resp.Header.Add(k, v)
+		}
+	}
+	resp.SetBody(body)
+	return nil
+}
+
+// matches menjalankan semua matcher terhadap pasangan permintaan.
+func (c *Cassette) matches(live, recorded *CassetteRequest) bool {
+	for _, m := range c.cfg.Matchers {
+		if !m(live, recorded) {
+			return false
+		}
+	}
+	return true
+}
+
+// captureRequest mengubah req menjadi CassetteRequest yang sudah diredaksi.
+// Body stream tidak dapat dibaca ulang sehingga tidak ikut direkam.
+func (c *Cassette) captureRequest(req *fasthttp.Request) CassetteRequest {
+	cr := CassetteRequest{
+		Method:  string(req.Header.Method()),
+		URL:     redactURL(req.URI().String(), c.cfg.RedactFields),
+		Headers: requestHeader(req),
+	}
+	redactHeaders(cr.Headers, c.cfg.RedactHeaders)
+	if !req.IsBodyStream() {
+		cr.Body, cr.Encoding = encodeCassetteBody(req.Body())
+		if cr.Encoding == "" {
+			cr.Body = redactBody(cr.Body, string(req.Header.ContentType()), c.cfg.RedactFields)
+		}
+	}
+	return cr
+}
+
+// encodeCassetteBody mengubah body menjadi string; body non-UTF-8 disimpan sebagai base64.
+func encodeCassetteBody(body []byte) (string, string) {
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+	return base64.StdEncoding.EncodeToString(body), "base64"
+}
+
+// decodeCassetteBody adalah kebalikan dari encodeCassetteBody.
+func decodeCassetteBody(body, encoding string) ([]byte, error) {
+	if encoding == "base64" {
+		return base64.StdEncoding.DecodeString(body)
+	}
+	return []byte(body), nil
+}
+
+// normalizeCassetteURL mengurutkan parameter query agar perbandingan URL tidak bergantung urutan.
+// Jika withHost bernilai false, skema dan host diabaikan.
+func normalizeCassetteURL(raw string, withHost bool) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	u.RawQuery = u.Query().Encode()
+	u.Fragment = ""
+	if !withHost {
+		u.Scheme = ""
+		u.Host = ""
+		u.User = nil
+	}
+	return u.String()
+}
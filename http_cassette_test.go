@@ -0,0 +1,150 @@
+package gocommon
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestCassette_RecordAndReplay(t *testing.T) {
+	for _, name := range []string{"partner.yaml", "partner.json"} {
+		t.Run(name, func(t *testing.T) {
+			var calls int32
+			handler := func(ctx *fasthttp.RequestCtx) {
+				atomic.AddInt32(&calls, 1)
+				ctx.SetContentType("application/json")
+				switch string(ctx.Path()) {
+				case "/token":
+					ctx.SetBodyString(`{"access_token":"secret-token","expires_in":3600}`)
+				default:
+					ctx.Response.Header.Set("X-Request-Id", "abc")
+					ctx.SetBodyString(`{"amount":` + string(ctx.QueryArgs().Peek("amount")) + `}`)
+				}
+			}
+			addr, closeServer, err := startTestServer(handler)
+			require.NoError(t, err)
+
+			path := filepath.Join(t.TempDir(), "cassettes", name)
+			rec, err := NewCassette(path, CassetteConfig{})
+			require.NoError(t, err)
+			require.True(t, rec.Recording())
+
+			client := NewHTTPClient(WithBaseURL(addr), WithCassette(rec))
+			_, _, err = client.PostForm("/token", map[string]string{"Authorization": "Basic c2VjcmV0"},
+				map[string]string{"client_secret": "s3cr3t", "grant_type": "client_credentials"})
+			require.NoError(t, err)
+			for _, amount := range []string{"1", "2"} {
+				_, _, err = client.GetJSON("/inquiry?amount="+amount, nil)
+				require.NoError(t, err)
+			}
+			closeServer()
+			require.EqualValues(t, 3, atomic.LoadInt32(&calls))
+
+			raw, err := os.ReadFile(path)
+			require.NoError(t, err)
+			require.NotContains(t, string(raw), "secret-token")
+			require.NotContains(t, string(raw), "s3cr3t")
+			require.NotContains(t, string(raw), "c2VjcmV0")
+			require.Contains(t, string(raw), redactedValue)
+
+			// Replay works with the server gone.
+			play, err := NewCassette(path, CassetteConfig{Matchers: []CassetteMatcher{MatchMethod, MatchPath, MatchBody}})
+			require.NoError(t, err)
+			require.False(t, play.Recording())
+			client = NewHTTPClient(WithBaseURL(addr), WithCassette(play))
+
+			resp, status, err := client.GetJSON("/inquiry?amount=2", nil)
+			require.NoError(t, err)
+			require.Equal(t, fasthttp.StatusOK, status)
+			require.JSONEq(t, `{"amount":2}`, string(resp))
+
+			resp, _, err = client.PostForm("/token", nil,
+				map[string]string{"grant_type": "client_credentials", "client_secret": "other"})
+			require.NoError(t, err)
+			require.JSONEq(t, `{"access_token":"[REDACTED]","expires_in":3600}`, string(resp))
+
+			resp, _, err = client.GetJSON("/inquiry?amount=1", nil)
+			require.NoError(t, err)
+			require.JSONEq(t, `{"amount":1}`, string(resp))
+
+			// Each recording is replayed once.
+			_, _, err = client.GetJSON("/inquiry?amount=1", nil)
+			require.ErrorIs(t, err, ErrCassetteMiss)
+		})
+	}
+}
+
+func TestCassette_RedactsPaymentFieldsByDefault(t *testing.T) {
+	addr, closeServer, err := startTestServer(func(ctx *fasthttp.RequestCtx) {
+		ctx.SetContentType("application/json")
+		ctx.SetBodyString(`{"status":"ok","otp":"918273"}`)
+	})
+	require.NoError(t, err)
+	defer closeServer()
+
+	path := filepath.Join(t.TempDir(), "payment.yaml")
+	rec, err := NewCassette(path, CassetteConfig{})
+	require.NoError(t, err)
+	_, _, err = NewHTTPClient(WithCassette(rec)).PostJSON(addr+"/charge", nil,
+		map[string]string{"card_number": "4111111111111111", "cvv": "123", "pin": "654321"})
+	require.NoError(t, err)
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	for _, secret := range []string{"4111111111111111", "\"123\"", "654321", "918273"} {
+		require.NotContains(t, string(raw), secret)
+	}
+}
+
+func TestCassette_ReplayMatchers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "matchers.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+- request:
+    method: POST
+    url: http://partner.test/transfer
+    headers:
+      X-Partner-Id:
+        - p1
+    body: '{"amount": 10, "to": "123"}'
+  response:
+    status_code: 201
+    headers:
+      Content-Type:
+        - application/json
+    body: '{"status":"ok"}'
+- request:
+    method: GET
+    url: http://partner.test/file
+  response:
+    status_code: 200
+    body: AAEC/w==
+    encoding: base64
+`), 0o644))
+
+	cassette, err := NewCassette(path, CassetteConfig{
+		Mode:     CassetteReplay,
+		Matchers: []CassetteMatcher{MatchMethod, MatchURL, MatchBody, MatchHeaders("X-Partner-Id")},
+	})
+	require.NoError(t, err)
+	client := NewHTTPClient(WithCassette(cassette))
+
+	_, _, err = client.PostJSON("http://partner.test/transfer", map[string]string{"X-Partner-Id": "p2"},
+		map[string]interface{}{"to": "123", "amount": 10})
+	require.ErrorIs(t, err, ErrCassetteMiss)
+
+	resp, status, err := client.PostJSON("http://partner.test/transfer", map[string]string{"X-Partner-Id": "p1"},
+		map[string]interface{}{"to": "123", "amount": 10})
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusCreated, status)
+	require.JSONEq(t, `{"status":"ok"}`, string(resp))
+
+	var buf bytes.Buffer
+	_, err = client.Download("http://partner.test/file", nil, &buf)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0, 1, 2, 255}, buf.Bytes())
+}
@@ -0,0 +1,479 @@
+package gocommon
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// defaultHTTPTimeout adalah timeout permintaan bawaan jika tidak diatur di klien maupun per permintaan.
+const defaultHTTPTimeout = 10 * time.Second
+
+// httpDoer adalah kontrak minimal yang dipenuhi oleh fasthttp.Client maupun fasthttp.HostClient.
+type httpDoer interface {
+	DoDeadline(req *fasthttp.Request, resp *fasthttp.Response, deadline time.Time) error
+}
+
+// HTTPClient adalah klien HTTP yang dapat dikonfigurasi dan dipakai ulang untuk banyak permintaan.
+// Setiap instance membungkus fasthttp.Client (atau fasthttp.HostClient) miliknya sendiri sehingga
+// pool koneksi, ukuran buffer, dan timeout dapat diatur per partner API.
+//
+// Gunakan NewHTTPClient untuk membuat instance baru. HTTPClient aman digunakan secara konkuren.
+type HTTPClient struct {
+	baseURL string
+	headers map[string]string
+	timeout time.Duration
+
+	maxConnsPerHost     int
+	maxIdleConnDuration time.Duration
+	readBufferSize      int
+	writeBufferSize     int
+	readTimeout         time.Duration
+	writeTimeout        time.Duration
+	dialTimeout         time.Duration
+	dial                fasthttp.DialFunc
+	proxyURL            string
+
+	disableCompression          bool
+	requestEncoding             string
+	requestCompressionThreshold int
+
+	tls       tlsSettings
+	tlsConfig *tls.Config
+	configErr error
+
+	hostAddr  string
+	hostIsTLS bool
+
+	retry       *RetryPolicy
+	breaker     *CircuitBreaker
+	limiter     *RateLimiter
+	middlewares []HTTPMiddleware
+
+	statusErrors bool
+	strictJSON   bool
+
+	doer httpDoer
+
+	streamDoerOnce sync.Once
+	streamDoer     httpDoer
+	streamConns    sync.Map
+}
+
+// HTTPClientOption adalah fungsi opsi untuk mengatur HTTPClient pada saat pembuatan.
+type HTTPClientOption func(*HTTPClient)
+
+// WithBaseURL mengatur base URL yang akan digabungkan dengan path relatif pada setiap permintaan.
+// URL absolut (diawali skema seperti "https://") tetap digunakan apa adanya.
+func WithBaseURL(baseURL string) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithDefaultHeaders mengatur header yang selalu dikirim pada setiap permintaan.
+// Header yang diberikan per permintaan akan menimpa header bawaan dengan nama yang sama.
+func WithDefaultHeaders(headers map[string]string) HTTPClientOption {
+	return func(c *HTTPClient) {
+		for k, v := range headers {
+			c.headers[k] = v
+		}
+	}
+}
+
+// WithTimeout mengatur timeout bawaan untuk setiap permintaan. Default 10 detik.
+func WithTimeout(timeout time.Duration) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.timeout = timeout
+	}
+}
+
+// WithMaxConnsPerHost mengatur jumlah maksimum koneksi terbuka ke satu host.
+func WithMaxConnsPerHost(n int) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.maxConnsPerHost = n
+	}
+}
+
+// WithMaxIdleConnDuration mengatur berapa lama koneksi keep-alive yang menganggur dipertahankan.
+func WithMaxIdleConnDuration(d time.Duration) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.maxIdleConnDuration = d
+	}
+}
+
+// WithReadBufferSize mengatur ukuran buffer baca per koneksi (juga membatasi ukuran header respons).
+func WithReadBufferSize(n int) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.readBufferSize = n
+	}
+}
+
+// WithWriteBufferSize mengatur ukuran buffer tulis per koneksi.
+func WithWriteBufferSize(n int) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.writeBufferSize = n
+	}
+}
+
+// WithReadTimeout mengatur timeout maksimum untuk membaca respons lengkap dari koneksi.
+func WithReadTimeout(d time.Duration) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.readTimeout = d
+	}
+}
+
+// WithWriteTimeout mengatur timeout maksimum untuk menulis permintaan lengkap ke koneksi.
+func WithWriteTimeout(d time.Duration) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.writeTimeout = d
+	}
+}
+
+// WithDialTimeout mengatur timeout untuk membuka koneksi TCP baru.
+func WithDialTimeout(d time.Duration) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.dialTimeout = d
+	}
+}
+
+// WithDialFunc mengganti fungsi pembuka koneksi, misalnya untuk mengarahkan klien ke server
+// in-memory pada tes (lihat paket fakehttp). Jika diatur, WithDialTimeout diabaikan.
+func WithDialFunc(dial fasthttp.DialFunc) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.dial = dial
+	}
+}
+
+// WithHostClient membuat klien memakai fasthttp.HostClient yang terikat ke satu alamat (host:port).
+// Cocok untuk klien yang hanya berbicara dengan satu partner API karena menghindari lookup host per permintaan.
+// Semua permintaan dikirim ke addr, berapa pun host pada URL permintaan.
+func WithHostClient(addr string, isTLS bool) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.hostAddr = addr
+		c.hostIsTLS = isTLS
+	}
+}
+
+// WithRetryPolicy mengatur kebijakan retry bawaan untuk semua permintaan melalui klien ini.
+// Kebijakan per permintaan (WithRetry) akan menimpa kebijakan bawaan ini.
+func WithRetryPolicy(policy RetryPolicy) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.retry = &policy
+	}
+}
+
+// RequestOption adalah fungsi opsi untuk mengatur satu permintaan HTTP.
+type RequestOption func(*requestOptions)
+
+// requestOptions menampung pengaturan yang berlaku untuk satu permintaan.
+type requestOptions struct {
+	timeout        time.Duration
+	retry          *RetryPolicy
+	idempotencyKey string
+	errorBody      interface{}
+	statusError    bool
+	strictJSON     bool
+
+	rateLimitFailFast bool
+
+	bodyStream     io.Reader
+	bodyStreamSize int
+
+	progress   func(written, total int64)
+	maxSize    int64
+	resume     bool
+	maxResumes int
+}
+
+// withBodyStream mengirim body dari reader alih-alih byte slice. Ukuran -1 berarti chunked.
+func withBodyStream(r io.Reader, size int) RequestOption {
+	return func(ro *requestOptions) {
+		ro.bodyStream = r
+		ro.bodyStreamSize = size
+	}
+}
+
+// WithRequestTimeout mengatur timeout untuk satu permintaan (per percobaan jika retry aktif).
+func WithRequestTimeout(timeout time.Duration) RequestOption {
+	return func(ro *requestOptions) {
+		ro.timeout = timeout
+	}
+}
+
+// NewHTTPClient membuat HTTPClient baru dengan opsi yang diberikan.
+//
+// Contoh penggunaan:
+//
+//	client := NewHTTPClient(
+//	    WithBaseURL("https://api.partner.co.id"),
+//	    WithDefaultHeaders(map[string]string{"X-Api-Key": "rahasia"}),
+//	    WithTimeout(5*time.Second),
+//	    WithMaxConnsPerHost(100),
+//	)
+//	resp, status, err := client.GetJSON("/v1/banks", nil)
+func NewHTTPClient(opts ...HTTPClientOption) *HTTPClient {
+	c := &HTTPClient{
+		headers: make(map[string]string),
+		timeout: defaultHTTPTimeout,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if err := c.configureTransport(); err != nil && c.configErr == nil {
+		c.configErr = err
+	}
+	c.doer = c.newDoer(false)
+	return c
+}
+
+// newDoer membangun fasthttp.Client atau fasthttp.HostClient sesuai konfigurasi klien.
+// Jika stream bernilai true, body respons yang lebih besar dari streamBufferSize dibaca
+// secara streaming melalui Response.BodyStream.
+func (c *HTTPClient) newDoer(stream bool) httpDoer {
+	maxBodySize := 0
+	if stream {
+		maxBodySize = streamBufferSize
+	}
+
+	dial := c.dial
+	if dial == nil && c.dialTimeout > 0 {
+		dialTimeout := c.dialTimeout
+		dial = func(addr string) (net.Conn, error) {
+			return fasthttp.DialTimeout(addr, dialTimeout)
+		}
+	}
+
+	if stream {
+		dial = c.trackStreamConns(dial)
+	}
+
+	if c.hostAddr != "" {
+		return &fasthttp.HostClient{
+			Addr:                c.hostAddr,
+			IsTLS:               c.hostIsTLS,
+			TLSConfig:           c.tlsConfig,
+			Dial:                dial,
+			MaxConns:            c.maxConnsPerHost,
+			MaxIdleConnDuration: c.maxIdleConnDuration,
+			ReadBufferSize:      c.readBufferSize,
+			WriteBufferSize:     c.writeBufferSize,
+			ReadTimeout:         c.readTimeout,
+			WriteTimeout:        c.writeTimeout,
+			StreamResponseBody:  stream,
+			MaxResponseBodySize: maxBodySize,
+		}
+	}
+
+	return &fasthttp.Client{
+		TLSConfig:           c.tlsConfig,
+		Dial:                dial,
+		MaxConnsPerHost:     c.maxConnsPerHost,
+		MaxIdleConnDuration: c.maxIdleConnDuration,
+		ReadBufferSize:      c.readBufferSize,
+		WriteBufferSize:     c.writeBufferSize,
+		ReadTimeout:         c.readTimeout,
+		WriteTimeout:        c.writeTimeout,
+		StreamResponseBody:  stream,
+		MaxResponseBodySize: maxBodySize,
+	}
+}
+
+// streamingDoer mengembalikan doer dengan streaming body respons, dibuat saat pertama kali dibutuhkan.
+func (c *HTTPClient) streamingDoer() httpDoer {
+	c.streamDoerOnce.Do(func() {
+		c.streamDoer = c.newDoer(true)
+	})
+	return c.streamDoer
+}
+
+// newRequestOptions menggabungkan pengaturan bawaan klien dengan opsi per permintaan.
+func (c *HTTPClient) newRequestOptions(opts []RequestOption) *requestOptions {
+	ro := &requestOptions{
+		timeout:     c.timeout,
+		retry:       c.retry,
+		statusError: c.statusErrors,
+		strictJSON:  c.strictJSON,
+	}
+	if c.limiter != nil {
+		ro.rateLimitFailFast = c.limiter.cfg.FailFast
+	}
+	for _, opt := range opts {
+		opt(ro)
+	}
+	return ro
+}
+
+// resolveURL menggabungkan base URL klien dengan URL permintaan jika URL tersebut relatif.
+func (c *HTTPClient) resolveURL(url string) string {
+	if c.baseURL == "" || strings.Contains(url, "://") {
+		return url
+	}
+	if url == "" {
+		return c.baseURL
+	}
+	return c.baseURL + "/" + strings.TrimLeft(url, "/")
+}
+
+// Request mengirim permintaan HTTP melalui klien ini. Perilakunya sama dengan HTTPRequest,
+// ditambah base URL, header bawaan, dan pengaturan koneksi milik klien.
+//
+// Parameter timeout opsional dalam milidetik dan menimpa timeout bawaan klien.
+func (c *HTTPClient) Request(method, url string, headers map[string]string, body []byte, timeout ...int) ([]byte, int, error) {
+	var opts []RequestOption
+	if len(timeout) > 0 {
+		opts = append(opts, WithRequestTimeout(time.Duration(timeout[0])*time.Millisecond))
+	}
+	return c.Do(method, url, headers, body, opts...)
+}
+
+// Do mengirim permintaan HTTP melalui klien ini dengan opsi per permintaan seperti
+// WithRequestTimeout, WithRetry, atau WithIdempotencyKey.
+func (c *HTTPClient) Do(method, url string, headers map[string]string, body []byte, opts ...RequestOption) ([]byte, int, error) {
+	return c.DoContext(context.Background(), method, url, headers, body, opts...)
+}
+
+// DoContext sama dengan Do, tetapi menghormati pembatalan dan deadline dari ctx.
+func (c *HTTPClient) DoContext(ctx context.Context, method, url string, headers map[string]string, body []byte, opts ...RequestOption) ([]byte, int, error) {
+	res, err := c.execute(ctx, method, url, headers, body, c.newRequestOptions(opts))
+	if res == nil {
+		return nil, 0, err
+	}
+	return res.body, res.status, err
+}
+
+// httpResult adalah salinan respons yang tetap aman dipakai setelah objek fasthttp dikembalikan ke pool.
+type httpResult struct {
+	method string
+	url    string
+	status int
+	header http.Header
+	body   []byte
+}
+
+// execute membangun permintaan, mengirimkannya sesuai opsi ro, lalu menyalin respons ke httpResult.
+// Kegagalan jaringan dikembalikan sebagai *HTTPError dengan res nil. Jika ro.statusError aktif,
+// respons 4xx dan 5xx menghasilkan res beserta *HTTPError. Jika ro.strictJSON aktif, respons yang
+// bukan JSON valid menghasilkan res beserta *JSONResponseError.
+func (c *HTTPClient) execute(ctx context.Context, method, url string, headers map[string]string, body []byte, ro *requestOptions) (*httpResult, error) {
+	if c.configErr != nil {
+		return nil, c.configErr
+	}
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+
+	req.SetRequestURI(c.resolveURL(url))
+	req.Header.SetMethod(method)
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	if ro.idempotencyKey != "" {
+		req.Header.Set(IdempotencyKeyHeader, ro.idempotencyKey)
+	}
+	injectContextTrace(ctx, req)
+	if ro.bodyStream != nil {
+		req.SetBodyStream(ro.bodyStream, ro.bodyStreamSize)
+	} else if body != nil {
+		req.SetBody(body)
+	}
+	decode := !c.disableCompression && len(req.Header.Peek("Accept-Encoding")) == 0
+	if decode {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+
+	err := c.doWithRetry(ctx, req, resp, ro)
+	if err != nil {
+		return nil, &HTTPError{
+			Method: method,
+			URL:    req.URI().String(),
+			Err:    err,
+		}
+	}
+
+	res := &httpResult{
+		method: method,
+		url:    req.URI().String(),
+		status: resp.StatusCode(),
+		header: responseHeader(resp),
+		body:   make([]byte, len(resp.Body())),
+	}
+	copy(res.body, resp.Body())
+	if decode {
+		if err := decodeResponseBody(res, resp); err != nil {
+			return res, err
+		}
+	}
+
+	if ro.statusError && res.status >= 400 {
+		return res, newHTTPError(res)
+	}
+	if ro.strictJSON {
+		if err := validateJSONResult(res); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// responseHeader menyalin header respons fasthttp ke http.Header.
+func responseHeader(resp *fasthttp.Response) http.Header {
+	h := make(http.Header)
+	resp.Header.VisitAll(func(k, v []byte) {
+		h.Add(string(k), string(v))
+	})
+	return h
+}
+
+// GetJSON mengirim permintaan GET dengan header "Accept: application/json" melalui klien ini.
+// Perilakunya sama dengan HTTPGetJSON.
+func (c *HTTPClient) GetJSON(url string, headers map[string]string, opts ...RequestOption) ([]byte, int, error) {
+	return c.GetJSONContext(context.Background(), url, headers, opts...)
+}
+
+// GetJSONContext sama dengan GetJSON, tetapi menghormati pembatalan dan deadline dari ctx.
+func (c *HTTPClient) GetJSONContext(ctx context.Context, url string, headers map[string]string, opts ...RequestOption) ([]byte, int, error) {
+	h := copyHeaders(headers)
+	h["Accept"] = "application/json"
+	return c.DoContext(ctx, "GET", url, h, nil, opts...)
+}
+
+// PostJSON me-marshal body ke JSON lalu mengirim permintaan POST melalui klien ini.
+// Perilakunya sama dengan HTTPPostJSON.
+func (c *HTTPClient) PostJSON(url string, headers map[string]string, body interface{}, opts ...RequestOption) ([]byte, int, error) {
+	return c.PostJSONContext(context.Background(), url, headers, body, opts...)
+}
+
+// PostJSONContext sama dengan PostJSON, tetapi menghormati pembatalan dan deadline dari ctx.
+func (c *HTTPClient) PostJSONContext(ctx context.Context, url string, headers map[string]string, body interface{}, opts ...RequestOption) ([]byte, int, error) {
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	h := copyHeaders(headers)
+	h["Content-Type"] = "application/json"
+	h["Accept"] = "application/json"
+	return c.DoContext(ctx, "POST", url, h, jsonBody, opts...)
+}
+
+// copyHeaders menyalin map header agar map milik pemanggil tidak ikut berubah.
+func copyHeaders(headers map[string]string) map[string]string {
+	h := make(map[string]string, len(headers)+2)
+	for k, v := range headers {
+		h[k] = v
+	}
+	return h
+}
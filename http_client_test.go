@@ -0,0 +1,96 @@
+package gocommon
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestHTTPClient_BaseURLAndDefaultHeaders(t *testing.T) {
+	handler := func(ctx *fasthttp.RequestCtx) {
+		ctx.SetStatusCode(fasthttp.StatusOK)
+		ctx.SetBodyString(`{"path":"` + string(ctx.Path()) +
+			`","key":"` + string(ctx.Request.Header.Peek("X-Api-Key")) +
+			`","custom":"` + string(ctx.Request.Header.Peek("X-Custom")) + `"}`)
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	client := NewHTTPClient(
+		WithBaseURL(addr+"/api/"),
+		WithDefaultHeaders(map[string]string{"X-Api-Key": "secret", "X-Custom": "default"}),
+	)
+
+	resp, status, err := client.GetJSON("/v1/banks", map[string]string{"X-Custom": "override"})
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusOK, status)
+	require.JSONEq(t, `{"path":"/api/v1/banks","key":"secret","custom":"override"}`, string(resp))
+
+	// Absolute URLs bypass the base URL.
+	resp, _, err = client.GetJSON(addr+"/other", nil)
+	require.NoError(t, err)
+	require.Contains(t, string(resp), `"path":"/other"`)
+}
+
+func TestHTTPClient_PostJSON_DoesNotMutateHeaders(t *testing.T) {
+	handler := func(ctx *fasthttp.RequestCtx) {
+		ctx.SetStatusCode(fasthttp.StatusOK)
+		ctx.SetBody(ctx.PostBody())
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	headers := map[string]string{"X-Test": "1"}
+	resp, status, err := NewHTTPClient().PostJSON(addr, headers, map[string]string{"foo": "bar"})
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusOK, status)
+	require.JSONEq(t, `{"foo":"bar"}`, string(resp))
+	require.Equal(t, map[string]string{"X-Test": "1"}, headers)
+}
+
+func TestHTTPClient_Timeout(t *testing.T) {
+	handler := func(ctx *fasthttp.RequestCtx) {
+		time.Sleep(200 * time.Millisecond)
+		ctx.SetStatusCode(fasthttp.StatusOK)
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	client := NewHTTPClient(WithTimeout(50 * time.Millisecond))
+	_, _, err = client.Request("GET", addr, nil, nil)
+	require.Error(t, err)
+
+	// Per-call timeout overrides the client timeout.
+	_, status, err := client.Request("GET", addr, nil, nil, 1000)
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusOK, status)
+}
+
+func TestHTTPClient_HostClient(t *testing.T) {
+	handler := func(ctx *fasthttp.RequestCtx) {
+		ctx.SetStatusCode(fasthttp.StatusOK)
+		ctx.SetBodyString(`{"ok":true}`)
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	client := NewHTTPClient(
+		WithHostClient(strings.TrimPrefix(addr, "http://"), false),
+		WithBaseURL(addr),
+		WithMaxConnsPerHost(4),
+		WithReadBufferSize(8192),
+		WithWriteBufferSize(8192),
+		WithDialTimeout(time.Second),
+	)
+	resp, status, err := client.GetJSON("/ping", nil)
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusOK, status)
+	require.JSONEq(t, `{"ok":true}`, string(resp))
+}
@@ -0,0 +1,94 @@
+package gocommon
+
+import (
+	"fmt"
+
+	"github.com/valyala/fasthttp"
+)
+
+// acceptEncoding adalah nilai header Accept-Encoding yang dikirim otomatis oleh klien.
+const acceptEncoding = "gzip, deflate, br"
+
+// WithDisableCompression mematikan kompresi respons otomatis. Secara default klien mengirim
+// header "Accept-Encoding: gzip, deflate, br" dan men-decode body respons sesuai Content-Encoding,
+// sehingga pemanggil selalu menerima body asli.
+//
+// Jika pemanggil mengatur header Accept-Encoding sendiri, body dikembalikan apa adanya tanpa decode.
+// Download (HTTPDownload, HTTPDownloadFile) tidak memakai kompresi otomatis agar resume dengan Range tetap valid.
+func WithDisableCompression() HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.disableCompression = true
+	}
+}
+
+// WithRequestCompression mengompresi body permintaan yang berukuran minimal threshold byte dengan
+// encoding "gzip", "deflate", atau "br", serta mengatur header Content-Encoding. Body stream dan
+// permintaan yang sudah memiliki Content-Encoding tidak diubah. Pastikan server tujuan mendukungnya.
+//
+// Kompresi dilakukan setelah seluruh middleware, tepat sebelum setiap percobaan dikirim, sehingga
+// middleware seperti SigningMiddleware, SlogMiddleware, dan cassette melihat body asli.
+//
+// Contoh penggunaan:
+//
+//	client := NewHTTPClient(WithRequestCompression("gzip", 4096))
+func WithRequestCompression(encoding string, threshold int) HTTPClientOption {
+	return func(c *HTTPClient) {
+		switch encoding {
+		case "gzip", "deflate", "br":
+			c.requestEncoding = encoding
+			c.requestCompressionThreshold = threshold
+		default:
+			if c.configErr == nil {
+				c.configErr = fmt.Errorf("unsupported request encoding %q", encoding)
+			}
+		}
+	}
+}
+
+// compressRequestBody mengompresi body req jika memenuhi pengaturan WithRequestCompression dan
+// mengembalikan fungsi untuk memulihkan body asli, agar percobaan berikutnya (retry) dan middleware
+// tetap melihat body yang belum dikompresi.
+func (c *HTTPClient) compressRequestBody(req *fasthttp.Request) (restore func()) {
+	restore = func() {}
+	if c.requestEncoding == "" || req.IsBodyStream() || len(req.Header.ContentEncoding()) > 0 {
+		return restore
+	}
+	body := req.Body()
+	if len(body) == 0 || len(body) < c.requestCompressionThreshold {
+		return restore
+	}
+
+	var compressed []byte
+	switch c.requestEncoding {
+	case "gzip":
+		compressed = fasthttp.AppendGzipBytes(nil, body)
+	case "deflate":
+		compressed = fasthttp.AppendDeflateBytes(nil, body)
+	case "br":
+		compressed = fasthttp.AppendBrotliBytes(nil, body)
+	}
+	original := append([]byte(nil), body...)
+	req.SetBodyRaw(compressed)
+	req.Header.SetContentEncoding(c.requestEncoding)
+	return func() {
+		req.SetBody(original)
+		req.Header.Del("Content-Encoding")
+	}
+}
+
+// decodeResponseBody men-decode body respons sesuai Content-Encoding lalu menghapus header
+// Content-Encoding dan Content-Length dari res karena tidak lagi sesuai dengan body.
+func decodeResponseBody(res *httpResult, resp *fasthttp.Response) error {
+	encoding := string(resp.Header.ContentEncoding())
+	if encoding == "" || encoding == "identity" {
+		return nil
+	}
+	body, err := resp.BodyUncompressed()
+	if err != nil {
+		return fmt.Errorf("decode %s response body: %w", encoding, err)
+	}
+	res.body = append(res.body[:0], body...)
+	res.header.Del("Content-Encoding")
+	res.header.Del("Content-Length")
+	return nil
+}
@@ -0,0 +1,166 @@
+package gocommon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestCompression_DecodesResponses(t *testing.T) {
+	payload := `{"data":"` + strings.Repeat("abc", 500) + `"}`
+	handler := func(ctx *fasthttp.RequestCtx) {
+		ctx.SetContentType("application/json")
+		encoding := string(ctx.QueryArgs().Peek("enc"))
+		ctx.Response.Header.Set("X-Accept-Encoding", string(ctx.Request.Header.Peek("Accept-Encoding")))
+		switch encoding {
+		case "gzip":
+			ctx.SetBody(fasthttp.AppendGzipBytes(nil, []byte(payload)))
+		case "deflate":
+			ctx.SetBody(fasthttp.AppendDeflateBytes(nil, []byte(payload)))
+		case "br":
+			ctx.SetBody(fasthttp.AppendBrotliBytes(nil, []byte(payload)))
+		default:
+			ctx.SetBodyString(payload)
+			return
+		}
+		ctx.Response.Header.SetContentEncoding(encoding)
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	for _, enc := range []string{"gzip", "deflate", "br", ""} {
+		resp, status, err := HTTPRequest("GET", addr+"/?enc="+enc, nil, nil)
+		require.NoError(t, err, enc)
+		require.Equal(t, fasthttp.StatusOK, status)
+		require.Equal(t, payload, string(resp), enc)
+	}
+
+	// Typed helpers and strict JSON validation see the decoded body.
+	out, err := GetJSONWithClient[map[string]string](NewHTTPClient(WithStrictJSON()), addr+"/?enc=gzip", nil)
+	require.NoError(t, err)
+	require.Len(t, out["data"], 1500)
+
+	// A caller-provided Accept-Encoding disables transparent decoding.
+	resp, _, err := HTTPRequest("GET", addr+"/?enc=gzip", map[string]string{"Accept-Encoding": "gzip"}, nil)
+	require.NoError(t, err)
+	require.NotEqual(t, payload, string(resp))
+
+	// Disabled compression sends no Accept-Encoding header.
+	client := NewHTTPClient(WithDisableCompression(), WithHTTPLogger(HTTPLogConfig{
+		Logger: slog.New(slog.NewJSONHandler(&bytes.Buffer{}, nil)),
+	}))
+	resp, _, err = client.GetJSON(addr+"/?enc=", nil)
+	require.NoError(t, err)
+	require.Equal(t, payload, string(resp))
+}
+
+func TestCompression_RequestBodies(t *testing.T) {
+	var gotEncoding, gotBody string
+	handler := func(ctx *fasthttp.RequestCtx) {
+		gotEncoding = string(ctx.Request.Header.ContentEncoding())
+		body, err := ctx.Request.BodyUncompressed()
+		require.NoError(t, err)
+		gotBody = string(body)
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	small := map[string]string{"a": "b"}
+	large := map[string]string{"a": strings.Repeat("x", 2048)}
+	largeJSON, _ := json.Marshal(large)
+
+	for _, enc := range []string{"gzip", "deflate", "br"} {
+		client := NewHTTPClient(WithRequestCompression(enc, 1024))
+		require.NoError(t, client.Err())
+
+		_, _, err = client.PostJSON(addr, nil, small)
+		require.NoError(t, err)
+		require.Empty(t, gotEncoding)
+		require.JSONEq(t, `{"a":"b"}`, gotBody)
+
+		_, _, err = client.PostJSON(addr, nil, large)
+		require.NoError(t, err)
+		require.Equal(t, enc, gotEncoding)
+		require.Equal(t, string(largeJSON), gotBody)
+	}
+
+	require.Error(t, NewHTTPClient(WithRequestCompression("lzma", 0)).Err())
+}
+
+func TestCompression_AfterMiddlewareWithSigningAndRetry(t *testing.T) {
+	signer := HMACSHA512Signer{Secret: []byte("client-secret")}
+	cfg := SigningConfig{MaxSkew: time.Minute}
+	var calls int
+	var encodings, verifyErrs []string
+	handler := func(ctx *fasthttp.RequestCtx) {
+		calls++
+		encodings = append(encodings, string(ctx.Request.Header.ContentEncoding()))
+		if err := VerifyRequestSignature(ctx, signer, cfg); err != nil {
+			verifyErrs = append(verifyErrs, err.Error())
+		}
+		if calls == 1 {
+			ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
+		}
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	var seen []string
+	inspect := func(next HTTPHandler) HTTPHandler {
+		return func(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+			seen = append(seen, string(req.Header.ContentEncoding())+"|"+string(req.Body()))
+			return next(ctx, req, resp)
+		}
+	}
+	client := NewHTTPClient(WithSigner(signer, cfg), WithMiddleware(inspect), WithRequestCompression("gzip", 0))
+
+	body := map[string]string{"amount": "10000.00"}
+	_, status, err := client.PostJSONContext(context.Background(), addr, nil, body,
+		WithRetry(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}), WithIdempotencyKey("trx-1"))
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusOK, status)
+
+	// Middleware melihat body asli di setiap percobaan, sedangkan server menerima body terkompresi.
+	require.Equal(t, []string{`|{"amount":"10000.00"}`, `|{"amount":"10000.00"}`}, seen)
+	require.Equal(t, []string{"gzip", "gzip"}, encodings)
+	require.Empty(t, verifyErrs)
+}
+
+func TestCompression_CassetteStoresDecodedBody(t *testing.T) {
+	handler := func(ctx *fasthttp.RequestCtx) {
+		ctx.SetContentType("application/json")
+		ctx.SetBody(fasthttp.AppendGzipBytes(nil, []byte(`{"access_token":"secret","ok":true}`)))
+		ctx.Response.Header.SetContentEncoding("gzip")
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "gzip.yaml")
+	rec, err := NewCassette(path, CassetteConfig{})
+	require.NoError(t, err)
+	_, _, err = NewHTTPClient(WithCassette(rec)).GetJSON(addr, nil)
+	require.NoError(t, err)
+	closeServer()
+
+	in := rec.Interactions()
+	require.Len(t, in, 1)
+	require.JSONEq(t, `{"access_token":"[REDACTED]","ok":true}`, in[0].Response.Body)
+	require.Empty(t, in[0].Response.Headers.Get("Content-Encoding"))
+
+	play, err := NewCassette(path, CassetteConfig{})
+	require.NoError(t, err)
+	resp, _, err := NewHTTPClient(WithCassette(play)).GetJSON(addr, nil)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"access_token":"[REDACTED]","ok":true}`, string(resp))
+}
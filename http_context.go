@@ -0,0 +1,128 @@
+package gocommon
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// requestIDKey adalah kunci context untuk request ID.
+type requestIDKey struct{}
+
+// ContextWithRequestID mengembalikan context turunan yang membawa request ID. Nilai ini dapat dibaca
+// kembali oleh hook (misalnya RetryPolicy.OnAttempt) melalui RequestIDFromContext.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext mengembalikan request ID yang disimpan oleh ContextWithRequestID,
+// atau string kosong jika tidak ada.
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// HTTPRequestContext sama dengan HTTPRequest, tetapi menghormati pembatalan dan deadline dari ctx.
+// Jika ctx dibatalkan sebelum respons diterima, fungsi langsung kembali dengan ctx.Err().
+// Deadline ctx dipakai jika lebih awal daripada timeout permintaan.
+//
+// Contoh penggunaan di handler fasthttp:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+//	defer cancel()
+//	resp, status, err := HTTPRequestContext(ctx, "GET", url, nil, nil)
+func HTTPRequestContext(ctx context.Context, method, url string, headers map[string]string, body []byte, opts ...RequestOption) ([]byte, int, error) {
+	return DefaultHTTPClient.DoContext(ctx, method, url, headers, body, opts...)
+}
+
+// HTTPGetJSONContext sama dengan HTTPGetJSON, tetapi menghormati pembatalan dan deadline dari ctx.
+func HTTPGetJSONContext(ctx context.Context, url string, headers map[string]string, opts ...RequestOption) ([]byte, int, error) {
+	return DefaultHTTPClient.GetJSONContext(ctx, url, headers, opts...)
+}
+
+// HTTPPostJSONContext sama dengan HTTPPostJSON, tetapi menghormati pembatalan dan deadline dari ctx.
+func HTTPPostJSONContext(ctx context.Context, url string, headers map[string]string, body interface{}, opts ...RequestOption) ([]byte, int, error) {
+	return DefaultHTTPClient.PostJSONContext(ctx, url, headers, body, opts...)
+}
+
+// doAttempt mengirim satu percobaan permintaan dengan memperhatikan ctx.
+//
+// fasthttp tidak mendukung pembatalan, sehingga jika ctx dapat dibatalkan permintaan dikirim dari
+// goroutine terpisah memakai salinan req/resp. Bila ctx selesai lebih dulu, fungsi langsung kembali
+// dan goroutine tersebut membersihkan salinannya sendiri setelah fasthttp selesai.
+func (c *HTTPClient) doAttempt(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response, timeout time.Duration) error {
+	deadline, ctxDeadline := attemptDeadline(ctx, timeout)
+
+	done := ctx.Done()
+	if done == nil {
+		return c.doer.DoDeadline(req, resp, deadline)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r := fasthttp.AcquireRequest()
+	req.CopyTo(r)
+	if req.IsBodyStream() {
+		// CopyTo tidak menyalin body stream; pindahkan stream ke salinan.
+		r.SetBodyStream(req.BodyStream(), req.Header.ContentLength())
+	}
+	w := fasthttp.AcquireResponse()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- c.doer.DoDeadline(r, w, deadline)
+	}()
+
+	select {
+	case err := <-errCh:
+		switch {
+		case err == nil:
+			w.CopyTo(resp)
+		case ctx.Err() != nil:
+			err = ctx.Err()
+		case ctxDeadline && errors.Is(err, fasthttp.ErrTimeout):
+			// fasthttp bisa mendeteksi deadline sedikit lebih dulu daripada ctx.
+			err = context.DeadlineExceeded
+		}
+		fasthttp.ReleaseRequest(r)
+		fasthttp.ReleaseResponse(w)
+		return err
+	case <-done:
+		go func() {
+			<-errCh
+			fasthttp.ReleaseRequest(r)
+			fasthttp.ReleaseResponse(w)
+		}()
+		return ctx.Err()
+	}
+}
+
+// attemptDeadline menghitung deadline satu percobaan: sekarang ditambah timeout, atau deadline ctx
+// jika lebih awal. ctxDeadline bernilai true jika deadline ctx yang dipakai.
+func attemptDeadline(ctx context.Context, timeout time.Duration) (deadline time.Time, ctxDeadline bool) {
+	deadline = time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		return d, true
+	}
+	return deadline, false
+}
+
+// sleepContext menunggu selama d atau sampai ctx selesai, mana yang lebih dulu.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
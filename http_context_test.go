@@ -0,0 +1,114 @@
+package gocommon
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestHTTPRequestContext_Cancel(t *testing.T) {
+	handler := func(ctx *fasthttp.RequestCtx) {
+		time.Sleep(300 * time.Millisecond)
+		ctx.SetStatusCode(fasthttp.StatusOK)
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		cancel()
+	}()
+
+	start := time.Now()
+	_, _, err = HTTPRequestContext(ctx, "GET", addr, nil, nil)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Less(t, time.Since(start), 200*time.Millisecond)
+}
+
+func TestHTTPGetJSONContext_Deadline(t *testing.T) {
+	handler := func(ctx *fasthttp.RequestCtx) {
+		time.Sleep(300 * time.Millisecond)
+		ctx.SetStatusCode(fasthttp.StatusOK)
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, _, err = HTTPGetJSONContext(ctx, addr, nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestHTTPPostJSONContext_Success(t *testing.T) {
+	handler := func(ctx *fasthttp.RequestCtx) {
+		ctx.SetStatusCode(fasthttp.StatusOK)
+		ctx.SetBody(ctx.PostBody())
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	resp, status, err := HTTPPostJSONContext(ctx, addr, nil, map[string]int{"amount": 10000})
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusOK, status)
+	require.JSONEq(t, `{"amount":10000}`, string(resp))
+
+	out, err := PostJSONContext[map[string]int, map[string]int](ctx, addr, nil, map[string]int{"amount": 5})
+	require.NoError(t, err)
+	require.Equal(t, 5, out["amount"])
+}
+
+func TestHTTPClient_RetryHookReceivesRequestID(t *testing.T) {
+	var calls int32
+	handler := func(ctx *fasthttp.RequestCtx) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
+			return
+		}
+		ctx.SetStatusCode(fasthttp.StatusOK)
+		ctx.SetBodyString(`{}`)
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	var ids []string
+	ctx := ContextWithRequestID(context.Background(), "req-123")
+	_, err = GetJSONContext[map[string]any](ctx, addr, nil, WithRetry(RetryPolicy{
+		MaxAttempts: 2,
+		BaseDelay:   time.Millisecond,
+		OnAttempt:   func(a RetryAttempt) { ids = append(ids, RequestIDFromContext(a.Context)) },
+	}))
+	require.NoError(t, err)
+	require.Equal(t, []string{"req-123", "req-123"}, ids)
+}
+
+func TestHTTPClient_CancelDuringRetryBackoff(t *testing.T) {
+	handler := func(ctx *fasthttp.RequestCtx) {
+		ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	client := NewHTTPClient(WithRetryPolicy(RetryPolicy{MaxAttempts: 10, BaseDelay: time.Second, MaxDelay: time.Second}))
+	start := time.Now()
+	_, _, err = client.GetJSONContext(ctx, addr, nil)
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+	require.Less(t, time.Since(start), 500*time.Millisecond)
+}
@@ -0,0 +1,370 @@
+package gocommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/valyala/fasthttp"
+	"golang.org/x/net/publicsuffix"
+)
+
+// CookieJar menyimpan cookie dari header Set-Cookie dan mengirimkannya kembali pada permintaan
+// berikutnya sesuai aturan domain, path, masa berlaku, dan Secure (RFC 6265). CookieJar aman
+// digunakan secara konkuren dan juga memenuhi interface http.CookieJar.
+//
+// Contoh penggunaan:
+//
+//	jar, err := LoadCookieJar("session/portal.json")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	client := NewHTTPClient(WithBaseURL("https://portal.partner.co.id"), WithCookieJar(jar))
+//	_, _, err = client.PostForm("/login", nil, map[string]string{"user": "u", "password": "p"})
+//	...
+//	defer jar.Save("session/portal.json")
+type CookieJar struct {
+	mu      sync.Mutex
+	entries map[string]*jarEntry
+	now     func() time.Time
+}
+
+// jarEntry adalah satu cookie yang tersimpan di CookieJar.
+type jarEntry struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Domain   string `json:"domain"`
+	Path     string `json:"path"`
+	HostOnly bool   `json:"host_only,omitempty"`
+	Secure   bool   `json:"secure,omitempty"`
+	HttpOnly bool   `json:"http_only,omitempty"`
+	// Expires bernilai nol untuk cookie sesi.
+	Expires time.Time `json:"expires,omitzero"`
+	Created time.Time `json:"created"`
+}
+
+var _ http.CookieJar = (*CookieJar)(nil)
+
+// NewCookieJar membuat CookieJar kosong di memori.
+func NewCookieJar() *CookieJar {
+	return &CookieJar{
+		entries: make(map[string]*jarEntry),
+		now:     time.Now,
+	}
+}
+
+// LoadCookieJar membuat CookieJar dari file yang sebelumnya ditulis oleh Save. Jika file belum ada,
+// jar kosong dikembalikan tanpa error. Cookie yang sudah kedaluwarsa dibuang.
+func LoadCookieJar(path string) (*CookieJar, error) {
+	j := NewCookieJar()
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return j, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries []*jarEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("decode cookie jar: %w", err)
+	}
+	now := j.now()
+	for _, e := range entries {
+		if e.expired(now) {
+			continue
+		}
+		j.entries[e.key()] = e
+	}
+	return j, nil
+}
+
+// Save menulis seluruh cookie yang masih berlaku ke file dalam format JSON, termasuk cookie sesi,
+// agar sesi tetap berlaku setelah aplikasi dimulai ulang. File ditulis dengan izin 0600 karena
+// berisi token sesi.
+func (j *CookieJar) Save(path string) error {
+	j.mu.Lock()
+	now := j.now()
+	entries := make([]*jarEntry, 0, len(j.entries))
+	for key, e := range j.entries {
+		if e.expired(now) {
+			delete(j.entries, key)
+			continue
+		}
+		entries = append(entries, e)
+	}
+	sortJarEntries(entries)
+	data, err := json.MarshalIndent(entries, "", "  ")
+	j.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("encode cookie jar: %w", err)
+	}
+
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// SetCookies menyimpan cookie yang diterima dari respons untuk u. Cookie dengan atribut Domain yang
+// tidak cocok dengan host u atau berupa public suffix (misalnya "co.id") ditolak, begitu pula cookie
+// Secure yang diterima melalui HTTP biasa. Cookie dengan atribut Domain juga dikirim ke subdomainnya.
+// Cookie dengan Max-Age negatif atau Expires di masa lalu menghapus cookie yang tersimpan.
+func (j *CookieJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	host := canonicalCookieHost(u.Host)
+	if host == "" {
+		return
+	}
+	secure := u.Scheme == "https" || u.Scheme == "wss"
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	now := j.now()
+	for _, c := range cookies {
+		if c.Name == "" || (c.Secure && !secure) {
+			continue
+		}
+		e := &jarEntry{
+			Name:     c.Name,
+			Value:    c.Value,
+			Domain:   host,
+			Path:     c.Path,
+			HostOnly: true,
+			Secure:   c.Secure,
+			HttpOnly: c.HttpOnly,
+			Created:  now,
+		}
+		if c.Domain != "" {
+			domain := strings.ToLower(strings.TrimPrefix(c.Domain, "."))
+			switch {
+			case isPublicSuffix(domain):
+				// Public suffix hanya diterima dari host itu sendiri, sebagai cookie host-only
+				// (RFC 6265 bagian 5.3 langkah 5).
+				if domain != host {
+					continue
+				}
+			case cookieDomainAllowed(host, domain):
+				e.Domain = domain
+				e.HostOnly = false
+			default:
+				continue
+			}
+		}
+		if !strings.HasPrefix(e.Path, "/") {
+			e.Path = defaultCookiePath(u.Path)
+		}
+
+		key := e.key()
+		switch {
+		case c.MaxAge < 0:
+			delete(j.entries, key)
+			continue
+		case c.MaxAge > 0:
+			e.Expires = now.Add(time.Duration(c.MaxAge) * time.Second)
+		case !c.Expires.IsZero():
+			if !c.Expires.After(now) {
+				delete(j.entries, key)
+				continue
+			}
+			e.Expires = c.Expires
+		}
+		if old, ok := j.entries[key]; ok {
+			e.Created = old.Created
+		}
+		j.entries[key] = e
+	}
+}
+
+// Cookies mengembalikan cookie yang harus dikirim ke u, diurutkan dari path terpanjang.
+func (j *CookieJar) Cookies(u *url.URL) []*http.Cookie {
+	host := canonicalCookieHost(u.Host)
+	if host == "" {
+		return nil
+	}
+	secure := u.Scheme == "https" || u.Scheme == "wss"
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	j.mu.Lock()
+	now := j.now()
+	var matched []*jarEntry
+	for key, e := range j.entries {
+		if e.expired(now) {
+			delete(j.entries, key)
+			continue
+		}
+		if e.Secure && !secure {
+			continue
+		}
+		if e.HostOnly && e.Domain != host || !e.HostOnly && !cookieDomainMatch(host, e.Domain) {
+			continue
+		}
+		if !cookiePathMatch(path, e.Path) {
+			continue
+		}
+		matched = append(matched, e)
+	}
+	sortJarEntries(matched)
+	cookies := make([]*http.Cookie, len(matched))
+	for i, e := range matched {
+		cookies[i] = &http.Cookie{Name: e.Name, Value: e.Value}
+	}
+	j.mu.Unlock()
+	return cookies
+}
+
+// Clear menghapus semua cookie, misalnya setelah logout.
+func (j *CookieJar) Clear() {
+	j.mu.Lock()
+	j.entries = make(map[string]*jarEntry)
+	j.mu.Unlock()
+}
+
+// Middleware mengembalikan HTTPMiddleware yang menambahkan header Cookie pada setiap permintaan dan
+// menyimpan cookie dari header Set-Cookie pada respons. Cookie yang sudah diatur pemanggil tidak ditimpa.
+func (j *CookieJar) Middleware() HTTPMiddleware {
+	return func(next HTTPHandler) HTTPHandler {
+		return func(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+			// Header Cookie asal dipulihkan setelah percobaan agar retry dibangun ulang dari cookie
+			// pemanggil ditambah isi jar terbaru, bukan dari cookie jar percobaan sebelumnya.
+			u := requestURL(req)
+			original := string(req.Header.Peek("Cookie"))
+			for _, c := range j.Cookies(u) {
+				if len(req.Header.Cookie(c.Name)) == 0 {
+					req.Header.SetCookie(c.Name, c.Value)
+				}
+			}
+			err := next(ctx, req, resp)
+			req.Header.Del("Cookie")
+			restoreHeader(req, "Cookie", original)
+			if err != nil {
+				return err
+			}
+
+			var cookies []*http.Cookie
+			resp.Header.VisitAllCookie(func(_, value []byte) {
+				if c, err := http.ParseSetCookie(string(value)); err == nil {
+					cookies = append(cookies, c)
+				}
+			})
+			if len(cookies) > 0 {
+				j.SetCookies(u, cookies)
+			}
+			return nil
+		}
+	}
+}
+
+// WithCookieJar memasang jar pada klien sehingga sesi berbasis cookie dipertahankan antar permintaan.
+// Jar yang sama dapat dipakai bersama oleh beberapa klien.
+func WithCookieJar(jar *CookieJar) HTTPClientOption {
+	return WithMiddleware(jar.Middleware())
+}
+
+// key mengembalikan kunci unik cookie berdasarkan domain, path, dan nama.
+func (e *jarEntry) key() string {
+	return e.Domain + ";" + e.Path + ";" + e.Name
+}
+
+// expired melaporkan apakah cookie sudah kedaluwarsa pada now.
+func (e *jarEntry) expired(now time.Time) bool {
+	return !e.Expires.IsZero() && !e.Expires.After(now)
+}
+
+// sortJarEntries mengurutkan cookie dari path terpanjang, lalu dari yang paling lama dibuat.
+func sortJarEntries(entries []*jarEntry) {
+	sort.SliceStable(entries, func(a, b int) bool {
+		if len(entries[a].Path) != len(entries[b].Path) {
+			return len(entries[a].Path) > len(entries[b].Path)
+		}
+		if !entries[a].Created.Equal(entries[b].Created) {
+			return entries[a].Created.Before(entries[b].Created)
+		}
+		return entries[a].key() < entries[b].key()
+	})
+}
+
+// requestURL membangun url.URL dari URI permintaan fasthttp.
+func requestURL(req *fasthttp.Request) *url.URL {
+	uri := req.URI()
+	return &url.URL{
+		Scheme:  string(uri.Scheme()),
+		Host:    string(uri.Host()),
+		Path:    string(uri.Path()),
+		RawPath: string(uri.PathOriginal()),
+	}
+}
+
+// canonicalCookieHost mengembalikan host tanpa port dalam huruf kecil.
+func canonicalCookieHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(strings.TrimSuffix(strings.Trim(host, "[]"), "."))
+}
+
+// cookieDomainAllowed melaporkan apakah host boleh mengatur cookie untuk domain. Domain harus sama
+// dengan host atau induknya; pemeriksaan public suffix dilakukan terpisah oleh isPublicSuffix.
+func cookieDomainAllowed(host, domain string) bool {
+	if domain == host {
+		return true
+	}
+	if net.ParseIP(host) != nil {
+		return false
+	}
+	return cookieDomainMatch(host, domain)
+}
+
+// isPublicSuffix melaporkan apakah domain adalah public suffix seperti "com", "id", atau "co.id",
+// yang tidak boleh dipakai sebagai domain cookie.
+func isPublicSuffix(domain string) bool {
+	if net.ParseIP(domain) != nil {
+		return false
+	}
+	ps, _ := publicsuffix.PublicSuffix(domain)
+	return ps == domain
+}
+
+// cookieDomainMatch melaporkan apakah host cocok dengan domain cookie (RFC 6265 bagian 5.1.3).
+func cookieDomainMatch(host, domain string) bool {
+	return host == domain || strings.HasSuffix(host, "."+domain) && net.ParseIP(host) == nil
+}
+
+// cookiePathMatch melaporkan apakah path permintaan cocok dengan path cookie (RFC 6265 bagian 5.1.4).
+func cookiePathMatch(reqPath, cookiePath string) bool {
+	if reqPath == cookiePath {
+		return true
+	}
+	if !strings.HasPrefix(reqPath, cookiePath) {
+		return false
+	}
+	return strings.HasSuffix(cookiePath, "/") || reqPath[len(cookiePath)] == '/'
+}
+
+// defaultCookiePath menghitung path default cookie dari path permintaan (RFC 6265 bagian 5.1.4).
+func defaultCookiePath(reqPath string) string {
+	if !strings.HasPrefix(reqPath, "/") {
+		return "/"
+	}
+	i := strings.LastIndex(reqPath, "/")
+	if i == 0 {
+		return "/"
+	}
+	return reqPath[:i]
+}
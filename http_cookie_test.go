@@ -0,0 +1,186 @@
+package gocommon
+
+import (
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func cookieNames(cookies []*http.Cookie) []string {
+	names := make([]string, len(cookies))
+	for i, c := range cookies {
+		names[i] = c.Name + "=" + c.Value
+	}
+	return names
+}
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+	return u
+}
+
+func TestCookieJar_DomainAndPathRules(t *testing.T) {
+	jar := NewCookieJar()
+	jar.SetCookies(mustParseURL(t, "https://www.portal.co.id/app/login"), []*http.Cookie{
+		{Name: "host", Value: "1"},
+		{Name: "parent", Value: "2", Domain: ".portal.co.id", Path: "/"},
+		{Name: "admin", Value: "3", Path: "/admin"},
+		{Name: "tld", Value: "x", Domain: "id"},
+		{Name: "other", Value: "x", Domain: "evil.co.id"},
+	})
+
+	// Host-only cookie dengan path default "/app".
+	require.Equal(t, []string{"host=1", "parent=2"}, cookieNames(jar.Cookies(mustParseURL(t, "https://www.portal.co.id/app/home"))))
+	require.Equal(t, []string{"parent=2"}, cookieNames(jar.Cookies(mustParseURL(t, "https://www.portal.co.id/application"))))
+	require.Equal(t, []string{"parent=2"}, cookieNames(jar.Cookies(mustParseURL(t, "https://api.portal.co.id/app/home"))))
+	require.Equal(t, []string{"admin=3", "parent=2"}, cookieNames(jar.Cookies(mustParseURL(t, "https://www.portal.co.id/admin/users"))))
+	require.Empty(t, jar.Cookies(mustParseURL(t, "https://evil.co.id/")))
+	require.Empty(t, jar.Cookies(mustParseURL(t, "https://other.id/")))
+}
+
+func TestCookieJar_DomainAttribute(t *testing.T) {
+	jar := NewCookieJar()
+	// Domain sama dengan host tetap berlaku untuk subdomain (RFC 6265 bagian 5.3 langkah 6).
+	jar.SetCookies(mustParseURL(t, "https://example.com/"), []*http.Cookie{
+		{Name: "session", Value: "1", Domain: "example.com"},
+	})
+	require.Equal(t, []string{"session=1"}, cookieNames(jar.Cookies(mustParseURL(t, "https://www.example.com/"))))
+
+	// Public suffix yang lebih dari satu label juga ditolak.
+	jar.SetCookies(mustParseURL(t, "https://evil.co.id/"), []*http.Cookie{
+		{Name: "steal", Value: "x", Domain: "co.id"},
+	})
+	require.Empty(t, jar.Cookies(mustParseURL(t, "https://bank.co.id/")))
+	require.Empty(t, jar.Cookies(mustParseURL(t, "https://evil.co.id/")))
+
+	// Host yang merupakan public suffix hanya mendapat cookie host-only.
+	jar.SetCookies(mustParseURL(t, "https://github.io/"), []*http.Cookie{
+		{Name: "root", Value: "1", Domain: "github.io"},
+	})
+	require.Equal(t, []string{"root=1"}, cookieNames(jar.Cookies(mustParseURL(t, "https://github.io/"))))
+	require.Empty(t, jar.Cookies(mustParseURL(t, "https://user.github.io/")))
+}
+
+func TestCookieJar_ExpiryAndSecure(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	jar := NewCookieJar()
+	jar.now = func() time.Time { return now }
+
+	secureURL := mustParseURL(t, "https://portal.co.id/")
+	plainURL := mustParseURL(t, "http://portal.co.id/")
+	jar.SetCookies(secureURL, []*http.Cookie{
+		{Name: "sid", Value: "s", Secure: true},
+		{Name: "short", Value: "1", MaxAge: 60},
+		{Name: "dated", Value: "2", Expires: now.Add(time.Hour)},
+		{Name: "past", Value: "3", Expires: now.Add(-time.Hour)},
+	})
+	jar.SetCookies(plainURL, []*http.Cookie{{Name: "insecure", Value: "x", Secure: true}})
+
+	require.ElementsMatch(t, []string{"sid=s", "short=1", "dated=2"}, cookieNames(jar.Cookies(secureURL)))
+	require.ElementsMatch(t, []string{"short=1", "dated=2"}, cookieNames(jar.Cookies(plainURL)))
+
+	now = now.Add(2 * time.Minute)
+	require.ElementsMatch(t, []string{"sid=s", "dated=2"}, cookieNames(jar.Cookies(secureURL)))
+
+	// Max-Age negatif menghapus cookie.
+	jar.SetCookies(secureURL, []*http.Cookie{{Name: "dated", MaxAge: -1}})
+	require.Equal(t, []string{"sid=s"}, cookieNames(jar.Cookies(secureURL)))
+
+	jar.Clear()
+	require.Empty(t, jar.Cookies(secureURL))
+}
+
+func TestCookieJar_SaveAndLoad(t *testing.T) {
+	u := mustParseURL(t, "https://portal.co.id/")
+	jar := NewCookieJar()
+	jar.SetCookies(u, []*http.Cookie{
+		{Name: "sid", Value: "abc", HttpOnly: true},
+		{Name: "remember", Value: "yes", MaxAge: 3600},
+		{Name: "gone", Value: "x", MaxAge: 1},
+	})
+	jar.now = func() time.Time { return time.Now().Add(2 * time.Second) }
+
+	path := filepath.Join(t.TempDir(), "jar", "cookies.json")
+	require.NoError(t, jar.Save(path))
+
+	loaded, err := LoadCookieJar(path)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"sid=abc", "remember=yes"}, cookieNames(loaded.Cookies(u)))
+
+	empty, err := LoadCookieJar(filepath.Join(t.TempDir(), "missing.json"))
+	require.NoError(t, err)
+	require.Empty(t, empty.Cookies(u))
+}
+
+func TestCookieJar_ClientSession(t *testing.T) {
+	handler := func(ctx *fasthttp.RequestCtx) {
+		switch string(ctx.Path()) {
+		case "/login":
+			ctx.Response.Header.Add("Set-Cookie", "sid=abc123; Path=/; HttpOnly")
+			ctx.Response.Header.Add("Set-Cookie", "lang=id; Path=/account")
+		case "/logout":
+			ctx.Response.Header.Add("Set-Cookie", "sid=; Path=/; Max-Age=0")
+		default:
+			ctx.SetBodyString(string(ctx.Request.Header.Peek("Cookie")))
+		}
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	jar := NewCookieJar()
+	client := NewHTTPClient(WithBaseURL(addr), WithCookieJar(jar))
+
+	_, _, err = client.PostForm("/login", nil, map[string]string{"user": "u"})
+	require.NoError(t, err)
+
+	resp, _, err := client.GetJSON("/account/profile", nil)
+	require.NoError(t, err)
+	require.Equal(t, "lang=id; sid=abc123", string(resp))
+
+	resp, _, err = client.GetJSON("/home", map[string]string{"Cookie": "sid=override"})
+	require.NoError(t, err)
+	require.Equal(t, "sid=override", string(resp))
+
+	// Tanpa jar, cookie tidak dikirim.
+	resp, _, err = NewHTTPClient().GetJSON(addr+"/home", nil)
+	require.NoError(t, err)
+	require.Empty(t, string(resp))
+
+	_, _, err = client.GetJSON("/logout", nil)
+	require.NoError(t, err)
+	resp, _, err = client.GetJSON("/home", nil)
+	require.NoError(t, err)
+	require.Empty(t, string(resp))
+}
+
+func TestCookieJar_RetryUsesUpdatedCookies(t *testing.T) {
+	var calls int32
+	handler := func(ctx *fasthttp.RequestCtx) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			ctx.Response.Header.Add("Set-Cookie", "sid=new; Path=/")
+			ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
+			return
+		}
+		ctx.SetBodyString(string(ctx.Request.Header.Peek("Cookie")))
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	jar := NewCookieJar()
+	jar.SetCookies(mustParseURL(t, addr), []*http.Cookie{{Name: "sid", Value: "old", Path: "/"}})
+	client := NewHTTPClient(WithCookieJar(jar), WithRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}))
+
+	resp, status, err := client.GetJSON(addr+"/home", map[string]string{"Cookie": "lang=id"})
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusOK, status)
+	require.Equal(t, "lang=id; sid=new", string(resp))
+}
@@ -0,0 +1,467 @@
+package gocommon
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// streamBufferSize adalah ukuran buffer untuk membaca body respons secara streaming. Body respons
+// yang lebih kecil dari ukuran ini dibaca sekaligus oleh fasthttp.
+const streamBufferSize = 64 * 1024
+
+// ErrDownloadTooLarge dikembalikan jika ukuran download melebihi batas WithMaxSize.
+var ErrDownloadTooLarge = errors.New("download exceeds maximum size")
+
+// errRangeNotSupported dikembalikan jika download perlu dilanjutkan tetapi server tidak mendukung Range.
+var errRangeNotSupported = errors.New("server does not support range requests")
+
+// WithProgress mengatur callback yang dipanggil setiap kali potongan data download ditulis.
+// Nilai total bernilai -1 jika server tidak memberitahukan ukuran body. Hanya berlaku untuk download.
+func WithProgress(fn func(written, total int64)) RequestOption {
+	return func(ro *requestOptions) {
+		ro.progress = fn
+	}
+}
+
+// WithMaxSize membatasi ukuran download dalam byte. Jika terlampaui, download dihentikan dengan
+// ErrDownloadTooLarge. Hanya berlaku untuk download.
+func WithMaxSize(n int64) RequestOption {
+	return func(ro *requestOptions) {
+		ro.maxSize = n
+	}
+}
+
+// WithResume mengizinkan download dilanjutkan dengan header Range hingga maxResumes kali jika koneksi
+// terputus di tengah jalan. Untuk DownloadFile, file sementara ".part" dari percobaan sebelumnya juga
+// dilanjutkan alih-alih diunduh ulang dari awal. Hanya berlaku untuk download.
+func WithResume(maxResumes int) RequestOption {
+	return func(ro *requestOptions) {
+		ro.resume = true
+		ro.maxResumes = maxResumes
+	}
+}
+
+// HTTPDownload mengunduh body respons GET dari url dan menulisnya secara streaming ke w melalui
+// DefaultHTTPClient, sehingga body besar tidak dimuat ke memori. Fungsi mengembalikan jumlah byte
+// yang ditulis.
+//
+// Timeout permintaan (WithRequestTimeout atau timeout klien) membatasi waktu menunggu header respons
+// dan jeda antar potongan data yang diterima, bukan total durasi download. Untuk membatasi total
+// durasi, gunakan deadline ctx pada HTTPDownloadContext. Download tidak melalui retry maupun circuit breaker; gunakan WithResume untuk melanjutkan
+// koneksi yang terputus. Rate limiter klien (WithRateLimiter) tetap berlaku, dan setiap percobaan
+// resume memakai satu token.
+//
+// Contoh penggunaan:
+//
+//	var buf bytes.Buffer
+//	n, err := HTTPDownload(url, nil, &buf, WithMaxSize(50<<20), WithRequestTimeout(30*time.Second))
+func HTTPDownload(url string, headers map[string]string, w io.Writer, opts ...RequestOption) (int64, error) {
+	return DefaultHTTPClient.DownloadContext(context.Background(), url, headers, w, opts...)
+}
+
+// HTTPDownloadContext sama dengan HTTPDownload, tetapi menghormati pembatalan dan deadline dari ctx.
+// Jika ctx selesai saat body sedang dibaca, pembacaan yang menunggu data langsung dihentikan.
+func HTTPDownloadContext(ctx context.Context, url string, headers map[string]string, w io.Writer, opts ...RequestOption) (int64, error) {
+	return DefaultHTTPClient.DownloadContext(ctx, url, headers, w, opts...)
+}
+
+// HTTPDownloadFile mengunduh body respons GET dari url ke file di path melalui DefaultHTTPClient.
+// Data ditulis ke file sementara path+".part" lalu di-rename secara atomik ke path setelah selesai,
+// sehingga path tidak pernah berisi file setengah jadi.
+//
+// Contoh penggunaan:
+//
+//	n, err := HTTPDownloadFile(url, nil, "/data/settlement.csv",
+//	    WithResume(3),
+//	    WithProgress(func(written, total int64) {
+//	        log.Printf("%d/%d byte", written, total)
+//	    }),
+//	)
+func HTTPDownloadFile(url string, headers map[string]string, path string, opts ...RequestOption) (int64, error) {
+	return DefaultHTTPClient.DownloadFileContext(context.Background(), url, headers, path, opts...)
+}
+
+// HTTPDownloadFileContext sama dengan HTTPDownloadFile, tetapi menghormati pembatalan dan deadline dari ctx.
+func HTTPDownloadFileContext(ctx context.Context, url string, headers map[string]string, path string, opts ...RequestOption) (int64, error) {
+	return DefaultHTTPClient.DownloadFileContext(ctx, url, headers, path, opts...)
+}
+
+// Download mengunduh body respons ke w melalui klien ini. Perilakunya sama dengan HTTPDownload.
+func (c *HTTPClient) Download(url string, headers map[string]string, w io.Writer, opts ...RequestOption) (int64, error) {
+	return c.DownloadContext(context.Background(), url, headers, w, opts...)
+}
+
+// DownloadContext sama dengan Download, tetapi menghormati pembatalan dan deadline dari ctx.
+func (c *HTTPClient) DownloadContext(ctx context.Context, url string, headers map[string]string, w io.Writer, opts ...RequestOption) (int64, error) {
+	sink := &downloadSink{w: w}
+	err := c.download(ctx, url, headers, sink, c.newRequestOptions(opts))
+	return sink.written, err
+}
+
+// DownloadFile mengunduh body respons ke file di path melalui klien ini. Perilakunya sama dengan HTTPDownloadFile.
+func (c *HTTPClient) DownloadFile(url string, headers map[string]string, path string, opts ...RequestOption) (int64, error) {
+	return c.DownloadFileContext(context.Background(), url, headers, path, opts...)
+}
+
+// DownloadFileContext sama dengan DownloadFile, tetapi menghormati pembatalan dan deadline dari ctx.
+func (c *HTTPClient) DownloadFileContext(ctx context.Context, url string, headers map[string]string, path string, opts ...RequestOption) (int64, error) {
+	ro := c.newRequestOptions(opts)
+	tmp := path + ".part"
+
+	flag := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if ro.resume {
+		flag = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+	f, err := os.OpenFile(tmp, flag, 0o644)
+	if err != nil {
+		return 0, err
+	}
+
+	sink := &downloadSink{
+		w: f,
+		reset: func() error {
+			if err := f.Truncate(0); err != nil {
+				return err
+			}
+			_, err := f.Seek(0, io.SeekStart)
+			return err
+		},
+	}
+	if ro.resume {
+		info, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return 0, err
+		}
+		sink.written = info.Size()
+	}
+
+	err = c.download(ctx, url, headers, sink, ro)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		if !ro.resume {
+			os.Remove(tmp)
+		}
+		return sink.written, err
+	}
+	return sink.written, os.Rename(tmp, path)
+}
+
+// downloadSink adalah tujuan download beserta jumlah byte yang sudah ditulis.
+// reset dipakai untuk mengulang dari awal jika server mengabaikan Range; nil berarti tidak bisa diulang.
+type downloadSink struct {
+	w       io.Writer
+	written int64
+	reset   func() error
+}
+
+// download menjalankan downloadOnce dan melanjutkannya dengan Range jika koneksi terputus.
+func (c *HTTPClient) download(ctx context.Context, url string, headers map[string]string, sink *downloadSink, ro *requestOptions) error {
+	if c.configErr != nil {
+		return c.configErr
+	}
+	for resumes := 0; ; resumes++ {
+		err := c.downloadOnce(ctx, url, headers, sink, ro)
+		if err == nil {
+			return nil
+		}
+
+		var herr *HTTPError
+		statusErr := errors.As(err, &herr) && herr.StatusCode != 0
+		retryable := ro.resume && resumes < ro.maxResumes && ctx.Err() == nil && !statusErr &&
+			!errors.Is(err, ErrDownloadTooLarge) && !errors.Is(err, errRangeNotSupported) && !errors.Is(err, ErrRateLimited)
+		if !retryable {
+			return err
+		}
+	}
+}
+
+// downloadOnce mengirim satu permintaan GET (dengan Range jika sink sudah berisi data)
+// lalu menyalin body respons ke sink.
+func (c *HTTPClient) downloadOnce(ctx context.Context, url string, headers map[string]string, sink *downloadSink, ro *requestOptions) error {
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+
+	req.SetRequestURI(c.resolveURL(url))
+	req.Header.SetMethod(fasthttp.MethodGet)
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	injectContextTrace(ctx, req)
+	if sink.written > 0 {
+		req.Header.Set("Range", "bytes="+strconv.FormatInt(sink.written, 10)+"-")
+	}
+
+	host := string(req.URI().Host())
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx, host, ro.rateLimitFailFast); err != nil {
+			return &HTTPError{Method: fasthttp.MethodGet, URL: req.URI().String(), Err: err}
+		}
+	}
+
+	deadline, _ := attemptDeadline(ctx, ro.timeout)
+	send := c.chain(func(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+		return c.streamingDoer().DoDeadline(req, resp, deadline)
+	})
+	if err := send(ctx, req, resp); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+		return &HTTPError{Method: fasthttp.MethodGet, URL: req.URI().String(), Err: err}
+	}
+
+	if c.limiter != nil && resp.StatusCode() == fasthttp.StatusTooManyRequests {
+		if d, found := parseRetryAfter(resp.Header.Peek("Retry-After")); found {
+			c.limiter.Pause(host, d)
+		}
+	}
+
+	total := int64(resp.Header.ContentLength())
+	switch status := resp.StatusCode(); {
+	case status == fasthttp.StatusOK:
+		if sink.written > 0 {
+			// Server mengabaikan Range; mulai ulang dari awal jika memungkinkan.
+			if sink.reset == nil {
+				return errRangeNotSupported
+			}
+			if err := sink.reset(); err != nil {
+				return err
+			}
+			sink.written = 0
+		}
+	case status == fasthttp.StatusPartialContent:
+		start, size, ok := parseContentRange(string(resp.Header.Peek("Content-Range")))
+		if !ok || start != sink.written {
+			return fmt.Errorf("unexpected Content-Range %q for offset %d", resp.Header.Peek("Content-Range"), sink.written)
+		}
+		total = size
+		if total < 0 && resp.Header.ContentLength() >= 0 {
+			total = sink.written + int64(resp.Header.ContentLength())
+		}
+	case status == fasthttp.StatusRequestedRangeNotSatisfiable && sink.written > 0:
+		// File sementara sudah lengkap dari percobaan sebelumnya.
+		if _, size, ok := parseContentRange(string(resp.Header.Peek("Content-Range"))); ok && size == sink.written {
+			return nil
+		}
+		return newHTTPError(readStreamResult(req, resp))
+	default:
+		return newHTTPError(readStreamResult(req, resp))
+	}
+	if total < 0 {
+		total = -1
+	}
+
+	if ro.maxSize > 0 && total > ro.maxSize {
+		return ErrDownloadTooLarge
+	}
+
+	body := resp.BodyStream()
+	conn := c.streamConnFor(resp)
+	if body == nil {
+		// Middleware (misalnya Cassette) dapat mengisi body langsung tanpa stream.
+		body = bytes.NewReader(resp.Body())
+		conn = nil
+	}
+	if conn != nil {
+		// Membatalkan ctx memajukan deadline baca agar Read yang sedang menunggu langsung kembali.
+		stop := context.AfterFunc(ctx, func() { conn.SetReadDeadline(time.Now()) })
+		defer func() {
+			if !stop() {
+				// Deadline koneksi mungkin sudah diubah; jangan kembalikan ke pool.
+				resp.SetConnectionClose()
+			}
+		}()
+	}
+	if err := copyDownloadBody(ctx, body, conn, sink, ro, total); err != nil {
+		// Body belum habis dibaca sehingga koneksi tidak dapat dipakai ulang.
+		resp.SetConnectionClose()
+		return err
+	}
+	return nil
+}
+
+// copyDownloadBody menyalin body ke sink. Jika conn tidak nil, setiap pembacaan dibatasi oleh
+// ro.timeout sebagai idle timeout, menggantikan deadline permintaan yang hanya berlaku untuk header.
+func copyDownloadBody(ctx context.Context, body io.Reader, conn *streamConn, sink *downloadSink, ro *requestOptions, total int64) error {
+	buf := make([]byte, 32*1024)
+	for {
+		if conn != nil {
+			var deadline time.Time
+			if ro.timeout > 0 {
+				deadline = time.Now().Add(ro.timeout)
+			}
+			if err := conn.SetReadDeadline(deadline); err != nil {
+				return err
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n, readErr := body.Read(buf)
+		if n > 0 {
+			if ro.maxSize > 0 && sink.written+int64(n) > ro.maxSize {
+				return ErrDownloadTooLarge
+			}
+			if _, err := sink.w.Write(buf[:n]); err != nil {
+				return err
+			}
+			sink.written += int64(n)
+			if ro.progress != nil {
+				ro.progress(sink.written, total)
+			}
+		}
+		if readErr == io.EOF {
+			if total >= 0 && sink.written < total {
+				return io.ErrUnexpectedEOF
+			}
+			return nil
+		}
+		if readErr != nil {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			return readErr
+		}
+	}
+}
+
+// streamConn membungkus koneksi doer streaming agar deadline bacanya dapat diatur setelah header
+// respons diterima. fasthttp hanya memasang satu deadline baca per permintaan, yang tanpa ini ikut
+// membatasi pembacaan seluruh body.
+type streamConn struct {
+	net.Conn
+	conns *sync.Map
+	key   string
+}
+
+// Close menghapus koneksi dari daftar koneksi streaming lalu menutupnya.
+func (sc *streamConn) Close() error {
+	sc.conns.CompareAndDelete(sc.key, sc)
+	return sc.Conn.Close()
+}
+
+// tlsStreamConn adalah streamConn untuk koneksi yang sudah berupa TLS (misalnya dari WithDialFunc),
+// agar fasthttp tidak membungkusnya dengan TLS sekali lagi.
+type tlsStreamConn struct {
+	*streamConn
+	handshaker interface{ Handshake() error }
+}
+
+// Handshake meneruskan handshake ke koneksi TLS asli.
+func (tc *tlsStreamConn) Handshake() error {
+	return tc.handshaker.Handshake()
+}
+
+// trackStreamConns membungkus dial agar setiap koneksi baru tercatat di c.streamConns.
+func (c *HTTPClient) trackStreamConns(dial fasthttp.DialFunc) fasthttp.DialFunc {
+	if dial == nil {
+		dial = fasthttp.Dial
+	}
+	return func(addr string) (net.Conn, error) {
+		conn, err := dial(addr)
+		if err != nil {
+			return nil, err
+		}
+		sc := &streamConn{Conn: conn, conns: &c.streamConns, key: streamConnKey(conn.LocalAddr(), conn.RemoteAddr())}
+		if sc.key != "" {
+			c.streamConns.Store(sc.key, sc)
+		}
+		if hs, ok := conn.(interface{ Handshake() error }); ok {
+			return &tlsStreamConn{streamConn: sc, handshaker: hs}, nil
+		}
+		return sc, nil
+	}
+}
+
+// streamConnFor mengembalikan koneksi yang membawa resp, atau nil jika resp tidak berasal dari
+// doer streaming.
+func (c *HTTPClient) streamConnFor(resp *fasthttp.Response) *streamConn {
+	key := streamConnKey(resp.LocalAddr(), resp.RemoteAddr())
+	if key == "" {
+		return nil
+	}
+	v, ok := c.streamConns.Load(key)
+	if !ok {
+		return nil
+	}
+	return v.(*streamConn)
+}
+
+// streamConnKey membentuk kunci koneksi dari alamat lokal dan alamat tujuannya.
+func streamConnKey(local, remote net.Addr) string {
+	if local == nil || remote == nil {
+		return ""
+	}
+	return local.String() + "->" + remote.String()
+}
+
+// readStreamResult membaca sebagian body streaming untuk keperluan pesan error.
+func readStreamResult(req *fasthttp.Request, resp *fasthttp.Response) *httpResult {
+	res := &httpResult{
+		method: string(req.Header.Method()),
+		url:    req.URI().String(),
+		status: resp.StatusCode(),
+		header: responseHeader(resp),
+	}
+	if body := resp.BodyStream(); body != nil {
+		res.body, _ = io.ReadAll(io.LimitReader(body, httpErrorBodyLimit))
+	} else {
+		res.body = truncateBody(resp.Body(), httpErrorBodyLimit)
+	}
+	return res
+}
+
+// parseContentRange mem-parse header "bytes start-end/size". size bernilai -1 jika "*".
+// Format "bytes */size" (untuk status 416) menghasilkan start -1.
+func parseContentRange(v string) (start, size int64, ok bool) {
+	v, found := strings.CutPrefix(v, "bytes ")
+	if !found {
+		return 0, 0, false
+	}
+	rng, sz, found := strings.Cut(v, "/")
+	if !found {
+		return 0, 0, false
+	}
+
+	size = -1
+	if sz != "*" {
+		n, err := strconv.ParseInt(sz, 10, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+		size = n
+	}
+	if rng == "*" {
+		return -1, size, true
+	}
+	first, _, found := strings.Cut(rng, "-")
+	if !found {
+		return 0, 0, false
+	}
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return start, size, true
+}
@@ -0,0 +1,278 @@
+package gocommon
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// rangeHandler melayani content dengan dukungan header Range sederhana ("bytes=N-").
+func rangeHandler(content []byte) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		ctx.Response.Header.Set("Accept-Ranges", "bytes")
+		rng := string(ctx.Request.Header.Peek("Range"))
+		if rng == "" {
+			ctx.SetStatusCode(fasthttp.StatusOK)
+			ctx.SetBody(content)
+			return
+		}
+		start, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+		if start >= len(content) {
+			ctx.Response.Header.Set("Content-Range", fmt.Sprintf("bytes */%d", len(content)))
+			ctx.SetStatusCode(fasthttp.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		ctx.Response.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(content)-1, len(content)))
+		ctx.SetStatusCode(fasthttp.StatusPartialContent)
+		ctx.SetBody(content[start:])
+	}
+}
+
+func TestHTTPDownload_StreamsWithProgress(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 50*1024)
+	addr, closeServer, err := startTestServer(rangeHandler(content))
+	require.NoError(t, err)
+	defer closeServer()
+
+	var calls int
+	var lastTotal int64
+	var buf bytes.Buffer
+	n, err := HTTPDownload(addr, nil, &buf, WithProgress(func(written, total int64) {
+		calls++
+		lastTotal = total
+	}))
+	require.NoError(t, err)
+	require.EqualValues(t, len(content), n)
+	require.Equal(t, content, buf.Bytes())
+	require.Greater(t, calls, 1)
+	require.EqualValues(t, len(content), lastTotal)
+}
+
+func TestHTTPDownload_MaxSize(t *testing.T) {
+	content := bytes.Repeat([]byte("x"), 200*1024)
+	addr, closeServer, err := startTestServer(rangeHandler(content))
+	require.NoError(t, err)
+	defer closeServer()
+
+	var buf bytes.Buffer
+	_, err = HTTPDownload(addr, nil, &buf, WithMaxSize(1024))
+	require.ErrorIs(t, err, ErrDownloadTooLarge)
+	require.Zero(t, buf.Len())
+}
+
+func TestHTTPDownload_ErrorStatus(t *testing.T) {
+	handler := func(ctx *fasthttp.RequestCtx) {
+		ctx.SetStatusCode(fasthttp.StatusNotFound)
+		ctx.SetBodyString("not found")
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	var buf bytes.Buffer
+	_, err = HTTPDownload(addr, nil, &buf)
+	var herr *HTTPError
+	require.True(t, errors.As(err, &herr))
+	require.Equal(t, fasthttp.StatusNotFound, herr.StatusCode)
+	require.Equal(t, "not found", string(herr.Body))
+}
+
+func TestHTTPDownload_RateLimited(t *testing.T) {
+	var calls int32
+	content := []byte("laporan-harian")
+	handler := rangeHandler(content)
+	addr, closeServer, err := startTestServer(func(ctx *fasthttp.RequestCtx) {
+		atomic.AddInt32(&calls, 1)
+		handler(ctx)
+	})
+	require.NoError(t, err)
+	defer closeServer()
+
+	limiter := NewRateLimiter(RateLimiterConfig{Limit: RateLimit{Rate: 1}, FailFast: true})
+	client := NewHTTPClient(WithRateLimiter(limiter))
+
+	var buf bytes.Buffer
+	_, err = client.Download(addr, nil, &buf)
+	require.NoError(t, err)
+	require.Equal(t, content, buf.Bytes())
+
+	_, err = client.Download(addr, nil, &buf, WithResume(3))
+	require.ErrorIs(t, err, ErrRateLimited)
+	require.EqualValues(t, 1, atomic.LoadInt32(&calls))
+}
+
+func TestHTTPDownloadFile_AtomicRenameAndResumePartFile(t *testing.T) {
+	content := []byte(strings.Repeat("settlement,", 10000))
+	addr, closeServer, err := startTestServer(rangeHandler(content))
+	require.NoError(t, err)
+	defer closeServer()
+
+	path := filepath.Join(t.TempDir(), "report.csv")
+
+	// Simulate an earlier interrupted run that left a partial file behind.
+	require.NoError(t, os.WriteFile(path+".part", content[:1000], 0o644))
+
+	n, err := HTTPDownloadFile(addr, nil, path, WithResume(1))
+	require.NoError(t, err)
+	require.EqualValues(t, len(content), n)
+
+	got, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, content, got)
+	_, err = os.Stat(path + ".part")
+	require.True(t, os.IsNotExist(err))
+
+	// Without resume an existing part file is discarded.
+	require.NoError(t, os.WriteFile(path+".part", []byte("garbage"), 0o644))
+	_, err = HTTPDownloadFile(addr, nil, path)
+	require.NoError(t, err)
+	got, err = os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, content, got)
+}
+
+func TestHTTPDownload_ResumesInterruptedStream(t *testing.T) {
+	content := bytes.Repeat([]byte("abcdefghij"), 20*1024)
+	half := len(content) / 2
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	var requests int32
+	var ranges atomic.Value
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			req, err := http.ReadRequest(bufio.NewReader(conn))
+			if err != nil {
+				conn.Close()
+				continue
+			}
+			if atomic.AddInt32(&requests, 1) == 1 {
+				// Promise the full body but drop the connection halfway.
+				fmt.Fprintf(conn, "HTTP/1.1 200 OK\r\nContent-Length: %d\r\nAccept-Ranges: bytes\r\n\r\n", len(content))
+				conn.Write(content[:half])
+				conn.Close()
+				continue
+			}
+			ranges.Store(req.Header.Get("Range"))
+			fmt.Fprintf(conn, "HTTP/1.1 206 Partial Content\r\nContent-Length: %d\r\nContent-Range: bytes %d-%d/%d\r\nConnection: close\r\n\r\n",
+				len(content)-half, half, len(content)-1, len(content))
+			conn.Write(content[half:])
+			conn.Close()
+		}
+	}()
+
+	var buf bytes.Buffer
+	n, err := NewHTTPClient().Download("http://"+ln.Addr().String(), nil, &buf, WithResume(2))
+	require.NoError(t, err)
+	require.EqualValues(t, len(content), n)
+	require.Equal(t, content, buf.Bytes())
+	require.Equal(t, fmt.Sprintf("bytes=%d-", half), ranges.Load())
+}
+
+// rawServer menjalankan server TCP yang menulis respons HTTP mentah lewat write untuk setiap koneksi.
+func rawServer(t *testing.T, write func(conn net.Conn)) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				if _, err := http.ReadRequest(bufio.NewReader(conn)); err != nil {
+					return
+				}
+				write(conn)
+			}()
+		}
+	}()
+	return "http://" + ln.Addr().String()
+}
+
+func TestHTTPDownload_TimeoutAppliesPerRead(t *testing.T) {
+	chunk := bytes.Repeat([]byte("x"), 20*1024)
+	addr := rawServer(t, func(conn net.Conn) {
+		fmt.Fprintf(conn, "HTTP/1.1 200 OK\r\nContent-Length: %d\r\n\r\n", 6*len(chunk))
+		for i := 0; i < 6; i++ {
+			conn.Write(chunk)
+			time.Sleep(60 * time.Millisecond)
+		}
+	})
+
+	// The transfer takes longer than the timeout, but no single read does.
+	var buf bytes.Buffer
+	n, err := NewHTTPClient().Download(addr, nil, &buf, WithRequestTimeout(200*time.Millisecond))
+	require.NoError(t, err)
+	require.EqualValues(t, 6*len(chunk), n)
+}
+
+func TestHTTPDownload_IdleTimeout(t *testing.T) {
+	addr := rawServer(t, func(conn net.Conn) {
+		fmt.Fprintf(conn, "HTTP/1.1 200 OK\r\nContent-Length: %d\r\n\r\n", 200*1024)
+		conn.Write(bytes.Repeat([]byte("x"), 100*1024))
+		time.Sleep(2 * time.Second)
+	})
+
+	start := time.Now()
+	_, err := NewHTTPClient().Download(addr, nil, io.Discard, WithRequestTimeout(100*time.Millisecond))
+	require.ErrorIs(t, err, os.ErrDeadlineExceeded)
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestHTTPDownloadContext_CancelStalledRead(t *testing.T) {
+	addr := rawServer(t, func(conn net.Conn) {
+		fmt.Fprintf(conn, "HTTP/1.1 200 OK\r\nContent-Length: %d\r\n\r\n", 200*1024)
+		conn.Write(bytes.Repeat([]byte("x"), 100*1024))
+		time.Sleep(2 * time.Second)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := NewHTTPClient().DownloadContext(ctx, addr, nil, io.Discard, WithRequestTimeout(10*time.Second))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestParseContentRange(t *testing.T) {
+	start, size, ok := parseContentRange("bytes 100-199/1000")
+	require.True(t, ok)
+	require.EqualValues(t, 100, start)
+	require.EqualValues(t, 1000, size)
+
+	start, size, ok = parseContentRange("bytes */1000")
+	require.True(t, ok)
+	require.EqualValues(t, -1, start)
+	require.EqualValues(t, 1000, size)
+
+	_, size, ok = parseContentRange("bytes 0-9/*")
+	require.True(t, ok)
+	require.EqualValues(t, -1, size)
+
+	_, _, ok = parseContentRange("items 0-9/10")
+	require.False(t, ok)
+}
@@ -0,0 +1,164 @@
+package gocommon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+
+	"github.com/valyala/fasthttp"
+)
+
+// httpErrorBodyLimit adalah jumlah byte maksimum body respons yang disimpan di HTTPError.
+const httpErrorBodyLimit = 1024
+
+// HTTPError adalah error terstruktur dari helper HTTP. Error ini mewakili dua jenis kegagalan:
+//   - kegagalan jaringan (timeout, DNS, koneksi ditolak, dan sebagainya), dengan Err berisi penyebabnya
+//     dan StatusCode bernilai 0;
+//   - respons dengan kode status error, dengan StatusCode, Header, dan Body terisi.
+//
+// Gunakan errors.As untuk mengambil detailnya:
+//
+//	var herr *HTTPError
+//	if errors.As(err, &herr) {
+//	    switch {
+//	    case herr.IsTimeout():
+//	        // coba lagi nanti
+//	    case herr.IsServerError():
+//	        log.Println(herr.StatusCode, herr.Header.Get("X-Request-Id"))
+//	    }
+//	}
+type HTTPError struct {
+	// Method adalah metode HTTP permintaan.
+	Method string
+	// URL adalah URL lengkap permintaan.
+	URL string
+	// StatusCode adalah kode status HTTP dari respons, atau 0 jika tidak ada respons.
+	StatusCode int
+	// Header berisi header respons.
+	Header http.Header
+	// Body adalah body respons mentah, dipotong maksimum 1024 byte.
+	Body []byte
+	// ErrorBody berisi body error yang sudah di-decode jika WithErrorBody digunakan dan decode berhasil.
+	ErrorBody interface{}
+	// Err adalah penyebab kegagalan jaringan, atau nil untuk error kode status.
+	Err error
+}
+
+// Error mengimplementasikan interface error.
+func (e *HTTPError) Error() string {
+	prefix := ""
+	if e.Method != "" || e.URL != "" {
+		prefix = e.Method + " " + e.URL + ": "
+	}
+	if e.Err != nil {
+		return prefix + e.Err.Error()
+	}
+	if len(e.Body) == 0 {
+		return fmt.Sprintf("%shttp status %d", prefix, e.StatusCode)
+	}
+	return fmt.Sprintf("%shttp status %d: %s", prefix, e.StatusCode, e.Body)
+}
+
+// Unwrap mengembalikan penyebab kegagalan jaringan sehingga errors.Is dan errors.As dapat
+// memeriksa error asalnya, misalnya context.DeadlineExceeded atau ErrCircuitOpen.
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// IsClientError melaporkan apakah respons berkode status 4xx.
+func (e *HTTPError) IsClientError() bool {
+	return e.StatusCode >= 400 && e.StatusCode <= 499
+}
+
+// IsServerError melaporkan apakah respons berkode status 5xx.
+func (e *HTTPError) IsServerError() bool {
+	return e.StatusCode >= 500 && e.StatusCode <= 599
+}
+
+// IsNetworkError melaporkan apakah error terjadi sebelum respons diterima.
+func (e *HTTPError) IsNetworkError() bool {
+	return e.Err != nil
+}
+
+// IsTimeout melaporkan apakah permintaan gagal karena timeout, termasuk deadline context.
+func (e *HTTPError) IsTimeout() bool {
+	if e.Err == nil {
+		return false
+	}
+	if errors.Is(e.Err, fasthttp.ErrTimeout) || errors.Is(e.Err, fasthttp.ErrDialTimeout) ||
+		errors.Is(e.Err, context.DeadlineExceeded) {
+		return true
+	}
+	var ne net.Error
+	return errors.As(e.Err, &ne) && ne.Timeout()
+}
+
+// IsConnRefused melaporkan apakah koneksi ke server ditolak (tidak ada yang listen di port tujuan).
+func (e *HTTPError) IsConnRefused() bool {
+	return e.Err != nil && errors.Is(e.Err, syscall.ECONNREFUSED)
+}
+
+// IsConnReset melaporkan apakah koneksi diputus oleh server sebelum respons lengkap diterima.
+func (e *HTTPError) IsConnReset() bool {
+	return e.Err != nil && (errors.Is(e.Err, syscall.ECONNRESET) || errors.Is(e.Err, fasthttp.ErrConnectionClosed))
+}
+
+// IsDNSError melaporkan apakah nama host tujuan gagal di-resolve.
+func (e *HTTPError) IsDNSError() bool {
+	var dnsErr *net.DNSError
+	return e.Err != nil && errors.As(e.Err, &dnsErr)
+}
+
+// IsCanceled melaporkan apakah permintaan dibatalkan melalui context.
+func (e *HTTPError) IsCanceled() bool {
+	return e.Err != nil && errors.Is(e.Err, context.Canceled)
+}
+
+// WithStatusErrors membuat semua permintaan melalui klien mengembalikan *HTTPError untuk respons
+// berkode status 4xx dan 5xx. Body dan kode status tetap dikembalikan bersama error tersebut.
+// Tanpa opsi ini, helper seperti HTTPRequest hanya mengembalikan error untuk kegagalan jaringan.
+func WithStatusErrors() HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.statusErrors = true
+	}
+}
+
+// WithStatusError mengatur apakah satu permintaan mengembalikan *HTTPError untuk respons 4xx dan 5xx,
+// menimpa pengaturan WithStatusErrors pada klien.
+//
+// Contoh penggunaan:
+//
+//	resp, status, err := HTTPPostJSONWithOptions(url, nil, body, WithStatusError(true))
+//	var herr *HTTPError
+//	if errors.As(err, &herr) && herr.IsClientError() {
+//	    // permintaan ditolak partner, jangan di-retry
+//	}
+func WithStatusError(enabled bool) RequestOption {
+	return func(ro *requestOptions) {
+		ro.statusError = enabled
+	}
+}
+
+// newHTTPError membuat HTTPError dari hasil respons dengan body yang sudah dipotong.
+func newHTTPError(res *httpResult) *HTTPError {
+	return &HTTPError{
+		Method:     res.method,
+		URL:        res.url,
+		StatusCode: res.status,
+		Header:     res.header,
+		Body:       truncateBody(res.body, httpErrorBodyLimit),
+	}
+}
+
+// truncateBody mengembalikan salinan body yang dipotong maksimum limit byte.
+func truncateBody(body []byte, limit int) []byte {
+	if len(body) > limit {
+		body = body[:limit]
+	}
+	out := make([]byte, len(body))
+	copy(out, body)
+	return out
+}
@@ -0,0 +1,102 @@
+package gocommon
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestHTTPError_NetworkClassification(t *testing.T) {
+	// Grab a free port and close it so the connection is refused.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := "http://" + ln.Addr().String()
+	ln.Close()
+
+	_, _, err = HTTPRequest("GET", addr, nil, nil, 500)
+	var herr *HTTPError
+	require.True(t, errors.As(err, &herr))
+	require.True(t, herr.IsNetworkError())
+	require.True(t, herr.IsConnRefused())
+	require.False(t, herr.IsTimeout())
+	require.Equal(t, "GET", herr.Method)
+	require.Equal(t, addr+"/", herr.URL)
+	require.Zero(t, herr.StatusCode)
+}
+
+func TestHTTPError_Timeout(t *testing.T) {
+	handler := func(ctx *fasthttp.RequestCtx) {
+		time.Sleep(200 * time.Millisecond)
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	_, _, err = HTTPRequest("GET", addr, nil, nil, 50)
+	var herr *HTTPError
+	require.True(t, errors.As(err, &herr))
+	require.True(t, herr.IsTimeout())
+	require.ErrorIs(t, err, fasthttp.ErrTimeout)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, _, err = HTTPRequestContext(ctx, "GET", addr, nil, nil)
+	require.True(t, errors.As(err, &herr))
+	require.True(t, herr.IsTimeout())
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestHTTPError_StatusErrorsOptIn(t *testing.T) {
+	handler := func(ctx *fasthttp.RequestCtx) {
+		switch string(ctx.Path()) {
+		case "/bad":
+			ctx.SetStatusCode(fasthttp.StatusBadRequest)
+			ctx.SetBodyString(`{"error":"invalid amount"}`)
+		case "/down":
+			ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
+		default:
+			ctx.SetStatusCode(fasthttp.StatusOK)
+		}
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	// Default behavior is unchanged: no error for error statuses.
+	_, status, err := HTTPGetJSON(addr+"/bad", nil)
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusBadRequest, status)
+
+	resp, status, err := HTTPGetJSON(addr+"/bad", nil, WithStatusError(true))
+	require.Equal(t, fasthttp.StatusBadRequest, status)
+	require.JSONEq(t, `{"error":"invalid amount"}`, string(resp))
+	var herr *HTTPError
+	require.True(t, errors.As(err, &herr))
+	require.True(t, herr.IsClientError())
+	require.False(t, herr.IsServerError())
+	require.False(t, herr.IsNetworkError())
+	require.Contains(t, herr.Error(), "GET "+addr+"/bad: http status 400")
+
+	client := NewHTTPClient(WithStatusErrors())
+	_, _, err = client.GetJSON(addr+"/down", nil)
+	require.True(t, errors.As(err, &herr))
+	require.True(t, herr.IsServerError())
+
+	_, _, err = client.GetJSON(addr+"/down", nil, WithStatusError(false))
+	require.NoError(t, err)
+
+	_, _, err = client.GetJSON(addr+"/ok", nil)
+	require.NoError(t, err)
+}
+
+func TestHTTPError_DNS(t *testing.T) {
+	herr := &HTTPError{Err: &net.DNSError{Err: "no such host", Name: "invalid.invalid", IsNotFound: true}}
+	require.True(t, herr.IsDNSError())
+	require.False(t, herr.IsConnRefused())
+	require.False(t, herr.IsCanceled())
+}
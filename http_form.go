@@ -0,0 +1,251 @@
+package gocommon
+
+import (
+	"context"
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HTTPPostForm mengirim permintaan POST dengan body application/x-www-form-urlencoded melalui
+// DefaultHTTPClient. Parameter form dapat berupa url.Values, map[string]string, map[string][]string,
+// atau struct (maupun pointer ke struct) dengan tag `form`; lihat EncodeForm untuk aturan encoding.
+//
+// Header, opsi per permintaan, dan nilai kembalian sama dengan HTTPPostJSONWithOptions.
+//
+// Contoh penggunaan:
+//
+//	type SendSMS struct {
+//	    To      []string `form:"to"`
+//	    Message string   `form:"message"`
+//	}
+//	resp, status, err := HTTPPostForm(url, nil, SendSMS{To: []string{"62812..."}, Message: "OTP 123456"})
+func HTTPPostForm(url string, headers map[string]string, form interface{}, opts ...RequestOption) ([]byte, int, error) {
+	return DefaultHTTPClient.PostFormContext(context.Background(), url, headers, form, opts...)
+}
+
+// HTTPPostFormContext sama dengan HTTPPostForm, tetapi menghormati pembatalan dan deadline dari ctx.
+func HTTPPostFormContext(ctx context.Context, url string, headers map[string]string, form interface{}, opts ...RequestOption) ([]byte, int, error) {
+	return DefaultHTTPClient.PostFormContext(ctx, url, headers, form, opts...)
+}
+
+// PostForm mengirim permintaan POST form-urlencoded melalui klien ini. Perilakunya sama dengan HTTPPostForm.
+func (c *HTTPClient) PostForm(url string, headers map[string]string, form interface{}, opts ...RequestOption) ([]byte, int, error) {
+	return c.PostFormContext(context.Background(), url, headers, form, opts...)
+}
+
+// PostFormContext sama dengan PostForm, tetapi menghormati pembatalan dan deadline dari ctx.
+func (c *HTTPClient) PostFormContext(ctx context.Context, url string, headers map[string]string, form interface{}, opts ...RequestOption) ([]byte, int, error) {
+	values, err := EncodeForm(form)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	h := copyHeaders(headers)
+	h["Content-Type"] = "application/x-www-form-urlencoded"
+	return c.DoContext(ctx, "POST", url, h, []byte(values.Encode()), opts...)
+}
+
+// EncodeForm mengubah v menjadi url.Values. Aturan encoding:
+//   - url.Values, map[string]string, dan map[string][]string dipakai apa adanya.
+//   - Field struct memakai nama dari tag `form`, atau nama field jika tag tidak ada.
+//     Tag `form:"-"` melewati field, dan opsi `omitempty` melewati nilai kosong.
+//     Struct embedded tanpa nama di tag diratakan ke struct induknya.
+//   - Slice dan array menjadi key berulang, misalnya to=a&to=b.
+//   - Struct dan map bersarang memakai notasi kurung, misalnya customer[name]=Budi.
+//     Slice berisi struct memakai indeks, misalnya items[0][sku]=A1.
+//   - time.Time di-format dengan RFC 3339; tipe yang mengimplementasikan encoding.TextMarshaler
+//     memakai MarshalText. Pointer nil dilewati.
+func EncodeForm(v interface{}) (url.Values, error) {
+	switch f := v.(type) {
+	case nil:
+		return url.Values{}, nil
+	case url.Values:
+		return f, nil
+	case map[string][]string:
+		return url.Values(f), nil
+	case map[string]string:
+		values := make(url.Values, len(f))
+		for k, s := range f {
+			values.Set(k, s)
+		}
+		return values, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return url.Values{}, nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct && rv.Kind() != reflect.Map {
+		return nil, fmt.Errorf("form: unsupported type %s", rv.Type())
+	}
+
+	values := make(url.Values)
+	if err := encodeFormValue(values, "", rv); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// encodeFormValue menambahkan rv ke values dengan key prefix secara rekursif.
+func encodeFormValue(values url.Values, prefix string, rv reflect.Value) error {
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	if s, ok, err := formScalar(rv); ok || err != nil {
+		if err != nil {
+			return err
+		}
+		values.Add(prefix, s)
+		return nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		rt := rv.Type()
+		for i := 0; i < rt.NumField(); i++ {
+			sf := rt.Field(i)
+			if !sf.IsExported() {
+				continue
+			}
+			name, named, omitEmpty := parseFormTag(sf)
+			if name == "-" {
+				continue
+			}
+			fv := rv.Field(i)
+			if omitEmpty && fv.IsZero() {
+				continue
+			}
+			if sf.Anonymous && !named && isEmbeddedFormStruct(sf.Type) && isFormComposite(fv) {
+				// Struct embedded tanpa nama di tag diratakan ke struct induk, seperti encoding/json.
+				if err := encodeFormValue(values, prefix, fv); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := encodeFormValue(values, formKey(prefix, name), fv); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("form: unsupported map key type %s", rv.Type().Key())
+		}
+		iter := rv.MapRange()
+		for iter.Next() {
+			if err := encodeFormValue(values, formKey(prefix, iter.Key().String()), iter.Value()); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			elem := rv.Index(i)
+			if isFormComposite(elem) {
+				if err := encodeFormValue(values, prefix+"["+strconv.Itoa(i)+"]", elem); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := encodeFormValue(values, prefix, elem); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("form: unsupported type %s for key %q", rv.Type(), prefix)
+	}
+	return nil
+}
+
+// formScalar mengubah nilai skalar menjadi string. ok bernilai false jika rv bukan skalar.
+func formScalar(rv reflect.Value) (s string, ok bool, err error) {
+	if rv.Type() == timeType {
+		return rv.Interface().(time.Time).Format(time.RFC3339), true, nil
+	}
+	if rv.Type().Implements(textMarshalerType) {
+		b, err := rv.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(b), true, err
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String(), true, nil
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), true, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), true, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), true, nil
+	case reflect.Float32:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 32), true, nil
+	case reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 64), true, nil
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return string(rv.Bytes()), true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// isFormComposite melaporkan apakah elemen slice perlu notasi indeks (struct atau map).
+func isFormComposite(rv reflect.Value) bool {
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return false
+		}
+		rv = rv.Elem()
+	}
+	if _, ok, _ := formScalar(rv); ok {
+		return false
+	}
+	return rv.Kind() == reflect.Struct || rv.Kind() == reflect.Map
+}
+
+// parseFormTag membaca nama dan opsi omitempty dari tag `form`. named bernilai false jika tag tidak
+// menyebut nama sehingga nama field yang dipakai.
+func parseFormTag(sf reflect.StructField) (name string, named, omitEmpty bool) {
+	tag := sf.Tag.Get("form")
+	name, opts, _ := strings.Cut(tag, ",")
+	named = name != ""
+	if !named {
+		name = sf.Name
+	}
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return name, named, omitEmpty
+}
+
+// isEmbeddedFormStruct melaporkan apakah tipe field embedded adalah struct atau pointer ke struct.
+func isEmbeddedFormStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// formKey menggabungkan prefix dan nama dengan notasi kurung.
+func formKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "[" + name + "]"
+}
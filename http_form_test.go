@@ -0,0 +1,138 @@
+package gocommon
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+type testFormItem struct {
+	SKU string `form:"sku"`
+	Qty int    `form:"qty"`
+}
+
+type testFormPayment struct {
+	OrderID  string   `form:"order_id"`
+	Amount   float64  `form:"amount"`
+	Channels []string `form:"channels"`
+	Customer struct {
+		Name  string `form:"name"`
+		Phone string `form:"phone,omitempty"`
+	} `form:"customer"`
+	Items    []testFormItem    `form:"items"`
+	Meta     map[string]string `form:"meta"`
+	PaidAt   *time.Time        `form:"paid_at"`
+	Secret   string            `form:"-"`
+	Note     string            `form:"note,omitempty"`
+	Untagged bool
+}
+
+func TestEncodeForm_Struct(t *testing.T) {
+	p := testFormPayment{
+		OrderID:  "INV-1",
+		Amount:   15000.5,
+		Channels: []string{"bca", "bri"},
+		Items:    []testFormItem{{"A1", 2}, {"B2", 1}},
+		Meta:     map[string]string{"source": "app"},
+		Secret:   "x",
+		Untagged: true,
+	}
+	p.Customer.Name = "Budi"
+
+	values, err := EncodeForm(&p)
+	require.NoError(t, err)
+	require.Equal(t, url.Values{
+		"order_id":       {"INV-1"},
+		"amount":         {"15000.5"},
+		"channels":       {"bca", "bri"},
+		"customer[name]": {"Budi"},
+		"items[0][sku]":  {"A1"},
+		"items[0][qty]":  {"2"},
+		"items[1][sku]":  {"B2"},
+		"items[1][qty]":  {"1"},
+		"meta[source]":   {"app"},
+		"Untagged":       {"true"},
+	}, values)
+}
+
+// TestFormBase dan TestFormAudit diekspor karena field embedded bertipe tak diekspor dilewati.
+type TestFormBase testFormItem
+
+type TestFormAudit struct {
+	CreatedBy string `form:"created_by"`
+	Channel   string `form:"channel,omitempty"`
+}
+
+type testFormRefund struct {
+	TestFormBase
+	*TestFormAudit
+	Reason string        `form:"reason,omitempty,string"`
+	Total  int           `form:",omitempty"`
+	Origin TestFormAudit `form:"origin"`
+	Parent testFormItem
+	Nested *testFormRefund `form:"nested,omitempty"`
+}
+
+func TestEncodeForm_EmbeddedAndTagOptions(t *testing.T) {
+	values, err := EncodeForm(testFormRefund{
+		TestFormBase:  TestFormBase{SKU: "A1", Qty: 2},
+		TestFormAudit: &TestFormAudit{CreatedBy: "ops"},
+		Origin:        TestFormAudit{CreatedBy: "app"},
+		Parent:        testFormItem{SKU: "P1"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, url.Values{
+		"sku":                {"A1"},
+		"qty":                {"2"},
+		"created_by":         {"ops"},
+		"origin[created_by]": {"app"},
+		"Parent[sku]":        {"P1"},
+		"Parent[qty]":        {"0"},
+	}, values)
+
+	// A nil embedded pointer is skipped like any other nil pointer.
+	values, err = EncodeForm(testFormRefund{Reason: "dup", Total: 5})
+	require.NoError(t, err)
+	require.Equal(t, []string{"dup"}, values["reason"])
+	require.Equal(t, []string{"5"}, values["Total"])
+	require.NotContains(t, values, "created_by")
+}
+
+func TestEncodeForm_MapsAndErrors(t *testing.T) {
+	values, err := EncodeForm(map[string]string{"a": "1"})
+	require.NoError(t, err)
+	require.Equal(t, "a=1", values.Encode())
+
+	values, err = EncodeForm(url.Values{"to": {"x", "y"}})
+	require.NoError(t, err)
+	require.Equal(t, "to=x&to=y", values.Encode())
+
+	_, err = EncodeForm(42)
+	require.Error(t, err)
+
+	_, err = EncodeForm(struct{ C chan int }{})
+	require.Error(t, err)
+}
+
+func TestHTTPPostForm(t *testing.T) {
+	handler := func(ctx *fasthttp.RequestCtx) {
+		ctx.SetStatusCode(fasthttp.StatusOK)
+		ctx.SetBodyString(string(ctx.Request.Header.ContentType()) + "|" + string(ctx.PostBody()))
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	form := struct {
+		To      []string `form:"to"`
+		Message string   `form:"message"`
+	}{To: []string{"6281", "6282"}, Message: "OTP 123456"}
+
+	resp, status, err := HTTPPostForm(addr, map[string]string{"X-Key": "k"}, form)
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusOK, status)
+	require.Equal(t, "application/x-www-form-urlencoded|message=OTP+123456&to=6281&to=6282", string(resp))
+}
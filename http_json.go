@@ -0,0 +1,125 @@
+package gocommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// WithErrorBody meminta helper JSON bertipe (GetJSON, PostJSON) untuk men-decode body respons non-2xx
+// ke v, yang harus berupa pointer. Hasilnya juga tersedia di HTTPError.ErrorBody.
+//
+// Contoh penggunaan:
+//
+//	var apiErr PartnerError
+//	banks, err := GetJSON[[]Bank](url, nil, WithErrorBody(&apiErr))
+//	if err != nil {
+//	    fmt.Println(apiErr.Code, apiErr.Message)
+//	}
+func WithErrorBody(v interface{}) RequestOption {
+	return func(ro *requestOptions) {
+		ro.errorBody = v
+	}
+}
+
+// GetJSON mengirim permintaan GET melalui DefaultHTTPClient dan men-decode body respons 2xx ke T.
+// Untuk respons non-2xx, fungsi mengembalikan *HTTPError berisi kode status, header, dan potongan body.
+//
+// Contoh penggunaan:
+//
+//	type Bank struct {
+//	    Code string `json:"code"`
+//	    Name string `json:"name"`
+//	}
+//	banks, err := GetJSON[[]Bank]("https://api.example.com/banks", nil)
+func GetJSON[T any](url string, headers map[string]string, opts ...RequestOption) (T, error) {
+	return GetJSONWithClient[T](DefaultHTTPClient, url, headers, opts...)
+}
+
+// GetJSONContext sama dengan GetJSON, tetapi menghormati pembatalan dan deadline dari ctx.
+func GetJSONContext[T any](ctx context.Context, url string, headers map[string]string, opts ...RequestOption) (T, error) {
+	return GetJSONWithClientContext[T](ctx, DefaultHTTPClient, url, headers, opts...)
+}
+
+// GetJSONWithClient sama dengan GetJSON, tetapi memakai klien c.
+func GetJSONWithClient[T any](c *HTTPClient, url string, headers map[string]string, opts ...RequestOption) (T, error) {
+	return GetJSONWithClientContext[T](context.Background(), c, url, headers, opts...)
+}
+
+// GetJSONWithClientContext sama dengan GetJSONWithClient, tetapi menghormati pembatalan dan deadline dari ctx.
+func GetJSONWithClientContext[T any](ctx context.Context, c *HTTPClient, url string, headers map[string]string, opts ...RequestOption) (T, error) {
+	h := copyHeaders(headers)
+	h["Accept"] = "application/json"
+	ro := c.newRequestOptions(opts)
+	ro.statusError = false
+
+	var out T
+	res, err := c.execute(ctx, "GET", url, h, nil, ro)
+	if err != nil {
+		return out, err
+	}
+	return decodeJSONResult[T](res, ro)
+}
+
+// PostJSON me-marshal body ke JSON, mengirimkannya dengan POST melalui DefaultHTTPClient,
+// lalu men-decode body respons 2xx ke Resp. Untuk respons non-2xx, fungsi mengembalikan *HTTPError.
+//
+// Contoh penggunaan:
+//
+//	resp, err := PostJSON[TransferRequest, TransferResponse](url, nil, TransferRequest{Amount: 10000})
+func PostJSON[Req, Resp any](url string, headers map[string]string, body Req, opts ...RequestOption) (Resp, error) {
+	return PostJSONWithClient[Req, Resp](DefaultHTTPClient, url, headers, body, opts...)
+}
+
+// PostJSONContext sama dengan PostJSON, tetapi menghormati pembatalan dan deadline dari ctx.
+func PostJSONContext[Req, Resp any](ctx context.Context, url string, headers map[string]string, body Req, opts ...RequestOption) (Resp, error) {
+	return PostJSONWithClientContext[Req, Resp](ctx, DefaultHTTPClient, url, headers, body, opts...)
+}
+
+// PostJSONWithClient sama dengan PostJSON, tetapi memakai klien c.
+func PostJSONWithClient[Req, Resp any](c *HTTPClient, url string, headers map[string]string, body Req, opts ...RequestOption) (Resp, error) {
+	return PostJSONWithClientContext[Req, Resp](context.Background(), c, url, headers, body, opts...)
+}
+
+// PostJSONWithClientContext sama dengan PostJSONWithClient, tetapi menghormati pembatalan dan deadline dari ctx.
+func PostJSONWithClientContext[Req, Resp any](ctx context.Context, c *HTTPClient, url string, headers map[string]string, body Req, opts ...RequestOption) (Resp, error) {
+	var out Resp
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return out, err
+	}
+
+	h := copyHeaders(headers)
+	h["Content-Type"] = "application/json"
+	h["Accept"] = "application/json"
+	ro := c.newRequestOptions(opts)
+	ro.statusError = false
+
+	res, err := c.execute(ctx, "POST", url, h, jsonBody, ro)
+	if err != nil {
+		return out, err
+	}
+	return decodeJSONResult[Resp](res, ro)
+}
+
+// decodeJSONResult men-decode respons 2xx ke T, atau membangun *HTTPError untuk status lainnya.
+func decodeJSONResult[T any](res *httpResult, ro *requestOptions) (T, error) {
+	var out T
+	if res.status < 200 || res.status > 299 {
+		herr := newHTTPError(res)
+		if ro.errorBody != nil && len(res.body) > 0 {
+			if json.Unmarshal(res.body, ro.errorBody) == nil {
+				herr.ErrorBody = ro.errorBody
+			}
+		}
+		return out, herr
+	}
+
+	if len(res.body) == 0 {
+		return out, nil
+	}
+	if err := json.Unmarshal(res.body, &out); err != nil {
+		return out, fmt.Errorf("decode json response: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,123 @@
+package gocommon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// jsonErrorSnippetLimit adalah jumlah byte maksimum body respons yang disimpan di JSONResponseError.
+const jsonErrorSnippetLimit = 256
+
+// ErrInvalidJSONResponse dikembalikan (dibungkus JSONResponseError) jika mode JSON ketat aktif dan
+// respons bukan JSON yang valid. Gunakan errors.Is untuk memeriksanya.
+var ErrInvalidJSONResponse = errors.New("invalid json response")
+
+// JSONResponseError adalah error bertipe yang dikembalikan oleh mode JSON ketat (lihat WithStrictJSON)
+// jika Content-Type respons bukan JSON atau body-nya bukan JSON yang valid, misalnya halaman error HTML
+// dari proxy. Error ini cocok dengan ErrInvalidJSONResponse melalui errors.Is.
+type JSONResponseError struct {
+	// Method adalah metode HTTP permintaan.
+	Method string
+	// URL adalah URL lengkap permintaan.
+	URL string
+	// StatusCode adalah kode status HTTP dari respons.
+	StatusCode int
+	// ContentType adalah nilai header Content-Type respons.
+	ContentType string
+	// Snippet adalah potongan awal body respons, maksimum 256 byte.
+	Snippet []byte
+	// Err adalah error parsing JSON, atau nil jika yang salah adalah Content-Type.
+	Err error
+}
+
+// Error mengimplementasikan interface error.
+func (e *JSONResponseError) Error() string {
+	reason := fmt.Sprintf("unexpected content-type %q", e.ContentType)
+	if e.Err != nil {
+		reason = e.Err.Error()
+	}
+	return fmt.Sprintf("%s %s: %s (status %d): %s: %s",
+		e.Method, e.URL, ErrInvalidJSONResponse.Error(), e.StatusCode, reason, e.Snippet)
+}
+
+// Unwrap mengembalikan error parsing JSON jika ada.
+func (e *JSONResponseError) Unwrap() error {
+	return e.Err
+}
+
+// Is membuat errors.Is(err, ErrInvalidJSONResponse) bernilai true.
+func (e *JSONResponseError) Is(target error) bool {
+	return target == ErrInvalidJSONResponse
+}
+
+// WithStrictJSON mengaktifkan mode JSON ketat untuk semua permintaan melalui klien. Pada mode ini
+// respons harus ber-Content-Type application/json (atau turunan +json) dan body-nya harus JSON
+// yang valid; jika tidak, *JSONResponseError dikembalikan bersama body dan kode status.
+// Respons dengan body kosong, misalnya 204 No Content, selalu diterima.
+//
+// Tanpa opsi ini klien bersifat longgar dan mengembalikan body apa adanya.
+func WithStrictJSON() HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.strictJSON = true
+	}
+}
+
+// WithJSONValidation mengatur mode JSON ketat untuk satu permintaan, menimpa pengaturan
+// WithStrictJSON pada klien. Gunakan WithJSONValidation(false) untuk endpoint yang sengaja
+// mengembalikan body mentah.
+//
+// Contoh penggunaan:
+//
+//	resp, status, err := HTTPGetJSON(url, nil, WithJSONValidation(true))
+//	if errors.Is(err, ErrInvalidJSONResponse) {
+//	    var jerr *JSONResponseError
+//	    errors.As(err, &jerr)
+//	    log.Printf("respons bukan JSON (%s): %s", jerr.ContentType, jerr.Snippet)
+//	}
+func WithJSONValidation(enabled bool) RequestOption {
+	return func(ro *requestOptions) {
+		ro.strictJSON = enabled
+	}
+}
+
+// validateJSONResult memeriksa Content-Type dan validitas body JSON pada res.
+func validateJSONResult(res *httpResult) error {
+	if len(res.body) == 0 {
+		return nil
+	}
+
+	contentType := res.header.Get("Content-Type")
+	newErr := func(err error) error {
+		return &JSONResponseError{
+			Method:      res.method,
+			URL:         res.url,
+			StatusCode:  res.status,
+			ContentType: contentType,
+			Snippet:     truncateBody(res.body, jsonErrorSnippetLimit),
+			Err:         err,
+		}
+	}
+
+	if !isJSONContentType(contentType) {
+		return newErr(nil)
+	}
+	if !json.Valid(res.body) {
+		// json.Valid tidak menjelaskan posisi kesalahan; Unmarshal dipakai untuk pesan error.
+		var v interface{}
+		return newErr(json.Unmarshal(res.body, &v))
+	}
+	return nil
+}
+
+// isJSONContentType melaporkan apakah Content-Type adalah application/json atau tipe +json
+// seperti application/problem+json.
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
@@ -0,0 +1,87 @@
+package gocommon
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestStrictJSON_Validation(t *testing.T) {
+	handler := func(ctx *fasthttp.RequestCtx) {
+		switch string(ctx.Path()) {
+		case "/ok":
+			ctx.SetContentType("application/json; charset=utf-8")
+			ctx.SetBodyString(`{"ok":true}`)
+		case "/problem":
+			ctx.SetContentType("application/problem+json")
+			ctx.SetBodyString(`{"title":"bad request"}`)
+		case "/html":
+			ctx.SetStatusCode(fasthttp.StatusBadGateway)
+			ctx.SetContentType("text/html")
+			ctx.SetBodyString("<html><body>502 Bad Gateway</body></html>")
+		case "/broken":
+			ctx.SetContentType("application/json")
+			ctx.SetBodyString(`{"ok":`)
+		case "/empty":
+			ctx.SetStatusCode(fasthttp.StatusNoContent)
+		}
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	// Lenient mode is the default: the HTML body is returned as-is.
+	resp, status, err := HTTPGetJSON(addr+"/html", nil)
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusBadGateway, status)
+	require.Contains(t, string(resp), "<html>")
+
+	client := NewHTTPClient(WithStrictJSON())
+
+	for _, path := range []string{"/ok", "/problem", "/empty"} {
+		_, _, err := client.GetJSON(addr+path, nil)
+		require.NoError(t, err, path)
+	}
+
+	resp, status, err = client.GetJSON(addr+"/html", nil)
+	require.ErrorIs(t, err, ErrInvalidJSONResponse)
+	require.Equal(t, fasthttp.StatusBadGateway, status)
+	require.Contains(t, string(resp), "<html>")
+	var jerr *JSONResponseError
+	require.True(t, errors.As(err, &jerr))
+	require.Equal(t, "text/html", jerr.ContentType)
+	require.Equal(t, fasthttp.StatusBadGateway, jerr.StatusCode)
+	require.Nil(t, jerr.Err)
+	require.Contains(t, err.Error(), "502 Bad Gateway")
+
+	_, _, err = client.GetJSON(addr+"/broken", nil)
+	require.True(t, errors.As(err, &jerr))
+	require.Error(t, jerr.Err)
+	require.Equal(t, `{"ok":`, string(jerr.Snippet))
+
+	// Per-request override keeps raw bodies available.
+	_, _, err = client.GetJSON(addr+"/html", nil, WithJSONValidation(false))
+	require.NoError(t, err)
+
+	_, _, err = HTTPGetJSON(addr+"/broken", nil, WithJSONValidation(true))
+	require.ErrorIs(t, err, ErrInvalidJSONResponse)
+}
+
+func TestStrictJSON_SnippetTruncated(t *testing.T) {
+	long := make([]byte, 4096)
+	for i := range long {
+		long[i] = 'x'
+	}
+	err := validateJSONResult(&httpResult{
+		method: "GET",
+		url:    "http://example.com",
+		status: 200,
+		header: map[string][]string{"Content-Type": {"text/plain"}},
+		body:   long,
+	})
+	var jerr *JSONResponseError
+	require.True(t, errors.As(err, &jerr))
+	require.Len(t, jerr.Snippet, jsonErrorSnippetLimit)
+}
@@ -0,0 +1,96 @@
+package gocommon
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+type testBank struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+type testAPIError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func TestGetJSON_DecodesSuccessBody(t *testing.T) {
+	handler := func(ctx *fasthttp.RequestCtx) {
+		ctx.SetStatusCode(fasthttp.StatusOK)
+		ctx.SetBodyString(`[{"code":"014","name":"BCA"},{"code":"008","name":"Mandiri"}]`)
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	banks, err := GetJSON[[]testBank](addr, nil)
+	require.NoError(t, err)
+	require.Equal(t, []testBank{{"014", "BCA"}, {"008", "Mandiri"}}, banks)
+}
+
+func TestGetJSON_ErrorBody(t *testing.T) {
+	handler := func(ctx *fasthttp.RequestCtx) {
+		ctx.Response.Header.Set("X-Request-Id", "req-1")
+		ctx.SetStatusCode(fasthttp.StatusNotFound)
+		ctx.SetBodyString(`{"code":"NOT_FOUND","message":"bank tidak ditemukan"}`)
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	var apiErr testAPIError
+	_, err = GetJSON[testBank](addr, nil, WithErrorBody(&apiErr))
+	require.Error(t, err)
+
+	var herr *HTTPError
+	require.True(t, errors.As(err, &herr))
+	require.Equal(t, fasthttp.StatusNotFound, herr.StatusCode)
+	require.Equal(t, "req-1", herr.Header.Get("X-Request-Id"))
+	require.Equal(t, &apiErr, herr.ErrorBody)
+	require.Equal(t, "NOT_FOUND", apiErr.Code)
+}
+
+func TestPostJSON_RoundTripAndTruncatedErrorBody(t *testing.T) {
+	handler := func(ctx *fasthttp.RequestCtx) {
+		if string(ctx.Path()) == "/fail" {
+			ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+			ctx.SetBodyString(strings.Repeat("x", 5000))
+			return
+		}
+		ctx.SetStatusCode(fasthttp.StatusCreated)
+		ctx.SetBody(ctx.PostBody())
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	client := NewHTTPClient(WithBaseURL(addr))
+	out, err := PostJSONWithClient[testBank, testBank](client, "/banks", nil, testBank{Code: "002", Name: "BRI"})
+	require.NoError(t, err)
+	require.Equal(t, testBank{Code: "002", Name: "BRI"}, out)
+
+	_, err = PostJSONWithClient[testBank, testBank](client, "/fail", nil, testBank{})
+	var herr *HTTPError
+	require.True(t, errors.As(err, &herr))
+	require.Equal(t, fasthttp.StatusInternalServerError, herr.StatusCode)
+	require.Len(t, herr.Body, httpErrorBodyLimit)
+	require.Nil(t, herr.ErrorBody)
+}
+
+func TestGetJSON_InvalidJSON(t *testing.T) {
+	handler := func(ctx *fasthttp.RequestCtx) {
+		ctx.SetStatusCode(fasthttp.StatusOK)
+		ctx.SetBodyString(`<html>bad gateway</html>`)
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	_, err = GetJSON[testBank](addr, nil)
+	require.Error(t, err)
+}
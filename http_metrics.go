@@ -0,0 +1,244 @@
+package gocommon
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// prometheusContentType adalah Content-Type format teks eksposisi Prometheus.
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultLatencyBuckets adalah batas atas bucket histogram latensi (dalam detik) jika
+// HTTPMetricsConfig.Buckets kosong.
+var DefaultLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// HTTPMetricsConfig berisi pengaturan HTTPMetrics.
+type HTTPMetricsConfig struct {
+	// Namespace adalah awalan nama metrik, misalnya "payment" menghasilkan
+	// "payment_http_client_requests_total". Default tanpa awalan.
+	Namespace string
+	// Buckets adalah batas atas bucket histogram latensi dalam detik, terurut naik.
+	// Default DefaultLatencyBuckets.
+	Buckets []float64
+}
+
+// HTTPMetrics adalah registry metrik permintaan HTTP keluar yang dapat ditampilkan dalam format
+// teks Prometheus tanpa bergantung pada library klien Prometheus. Metrik yang dicatat:
+//   - http_client_requests_total: jumlah permintaan;
+//   - http_client_request_errors_total: jumlah permintaan yang gagal karena error jaringan atau respons 5xx;
+//   - http_client_request_duration_seconds: histogram latensi.
+//
+// Semua metrik berlabel host, method, dan status_class ("2xx", "4xx", dan seterusnya, atau "error"
+// jika tidak ada respons). Setiap percobaan retry dicatat sebagai permintaan tersendiri.
+// HTTPMetrics aman digunakan secara konkuren.
+//
+// Contoh penggunaan:
+//
+//	metrics := NewHTTPMetrics(HTTPMetricsConfig{})
+//	UseHTTPMiddleware(metrics.Middleware()) // helper global seperti HTTPGetJSON
+//	client := NewHTTPClient(WithMetrics(metrics))
+//
+//	go fasthttp.ListenAndServe(":9100", metrics.Handler())
+type HTTPMetrics struct {
+	prefix  string
+	buckets []float64
+
+	mu       sync.Mutex
+	requests map[metricLabels]uint64
+	errors   map[metricLabels]uint64
+	latency  map[metricLabels]*latencyHistogram
+}
+
+// metricLabels adalah kombinasi label satu deret metrik.
+type metricLabels struct {
+	host, method, statusClass string
+}
+
+// latencyHistogram menyimpan hitungan per bucket (tidak kumulatif), jumlah, dan total observasi.
+type latencyHistogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// NewHTTPMetrics membuat registry metrik HTTP baru.
+func NewHTTPMetrics(cfg HTTPMetricsConfig) *HTTPMetrics {
+	buckets := cfg.Buckets
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	prefix := ""
+	if cfg.Namespace != "" {
+		prefix = cfg.Namespace + "_"
+	}
+	return &HTTPMetrics{
+		prefix:   prefix,
+		buckets:  buckets,
+		requests: make(map[metricLabels]uint64),
+		errors:   make(map[metricLabels]uint64),
+		latency:  make(map[metricLabels]*latencyHistogram),
+	}
+}
+
+// Middleware mengembalikan HTTPMiddleware yang mencatat setiap percobaan permintaan.
+func (m *HTTPMetrics) Middleware() HTTPMiddleware {
+	return func(next HTTPHandler) HTTPHandler {
+		return func(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+			start := time.Now()
+			err := next(ctx, req, resp)
+			status := 0
+			if err == nil {
+				status = resp.StatusCode()
+			}
+			m.Observe(string(req.URI().Host()), string(req.Header.Method()), status, time.Since(start))
+			return err
+		}
+	}
+}
+
+// WithMetrics memasang m pada klien sebagai middleware.
+func WithMetrics(m *HTTPMetrics) HTTPClientOption {
+	return WithMiddleware(m.Middleware())
+}
+
+// Observe mencatat satu permintaan secara manual. Status 0 berarti permintaan gagal tanpa respons.
+func (m *HTTPMetrics) Observe(host, method string, status int, duration time.Duration) {
+	labels := metricLabels{host: host, method: strings.ToUpper(method), statusClass: statusClass(status)}
+	seconds := duration.Seconds()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests[labels]++
+	if status == 0 || status >= 500 {
+		m.errors[labels]++
+	}
+	h := m.latency[labels]
+	if h == nil {
+		h = &latencyHistogram{counts: make([]uint64, len(m.buckets))}
+		m.latency[labels] = h
+	}
+	for i, le := range m.buckets {
+		if seconds <= le {
+			h.counts[i]++
+			break
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+// Reset menghapus semua metrik yang tercatat.
+func (m *HTTPMetrics) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = make(map[metricLabels]uint64)
+	m.errors = make(map[metricLabels]uint64)
+	m.latency = make(map[metricLabels]*latencyHistogram)
+}
+
+// WritePrometheus menulis semua metrik ke w dalam format teks eksposisi Prometheus.
+func (m *HTTPMetrics) WritePrometheus(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	m.mu.Lock()
+	m.writeCounter(bw, "http_client_requests_total", "Total outbound HTTP requests.", m.requests)
+	m.writeCounter(bw, "http_client_request_errors_total",
+		"Total outbound HTTP requests that failed with a network error or a 5xx response.", m.errors)
+	m.writeHistogram(bw)
+	m.mu.Unlock()
+
+	return bw.Flush()
+}
+
+// Handler mengembalikan handler fasthttp yang menampilkan metrik untuk di-scrape Prometheus.
+func (m *HTTPMetrics) Handler() fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		ctx.SetContentType(prometheusContentType)
+		if err := m.WritePrometheus(ctx); err != nil {
+			ctx.Error(err.Error(), fasthttp.StatusInternalServerError)
+		}
+	}
+}
+
+// writeCounter menulis satu metrik counter. Pemanggil wajib memegang m.mu.
+func (m *HTTPMetrics) writeCounter(w *bufio.Writer, name, help string, values map[metricLabels]uint64) {
+	name = m.prefix + name
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	for _, labels := range sortedLabels(values) {
+		fmt.Fprintf(w, "%s{%s} %d\n", name, labels.format(), values[labels])
+	}
+}
+
+// writeHistogram menulis histogram latensi. Pemanggil wajib memegang m.mu.
+func (m *HTTPMetrics) writeHistogram(w *bufio.Writer) {
+	name := m.prefix + "http_client_request_duration_seconds"
+	fmt.Fprintf(w, "# HELP %s Outbound HTTP request latency in seconds.\n# TYPE %s histogram\n", name, name)
+	for _, labels := range sortedLabels(m.latency) {
+		h := m.latency[labels]
+		base := labels.format()
+		var cumulative uint64
+		for i, le := range m.buckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(w, "%s_bucket{%s,le=\"%s\"} %d\n", name, base, formatFloat(le), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, base, h.count)
+		fmt.Fprintf(w, "%s_sum{%s} %s\n", name, base, formatFloat(h.sum))
+		fmt.Fprintf(w, "%s_count{%s} %d\n", name, base, h.count)
+	}
+}
+
+// format menulis label dalam format Prometheus.
+func (l metricLabels) format() string {
+	return `host="` + escapeLabelValue(l.host) + `",method="` + escapeLabelValue(l.method) +
+		`",status_class="` + escapeLabelValue(l.statusClass) + `"`
+}
+
+// sortedLabels mengembalikan kunci map dalam urutan yang stabil agar keluaran deterministik.
+func sortedLabels[V any](values map[metricLabels]V) []metricLabels {
+	keys := make([]metricLabels, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(a, b int) bool {
+		if keys[a].host != keys[b].host {
+			return keys[a].host < keys[b].host
+		}
+		if keys[a].method != keys[b].method {
+			return keys[a].method < keys[b].method
+		}
+		return keys[a].statusClass < keys[b].statusClass
+	})
+	return keys
+}
+
+// statusClass mengelompokkan kode status menjadi "1xx" sampai "5xx", atau "error" jika status 0.
+func statusClass(status int) string {
+	if status < 100 || status > 599 {
+		return "error"
+	}
+	return strconv.Itoa(status/100) + "xx"
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabelValue meng-escape backslash, tanda kutip, dan baris baru pada nilai label.
+func escapeLabelValue(v string) string {
+	return labelValueReplacer.Replace(v)
+}
+
+// formatFloat memformat angka sesuai format teks Prometheus.
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,102 @@
+package gocommon
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestHTTPMetrics_ClientRequests(t *testing.T) {
+	handler := func(ctx *fasthttp.RequestCtx) {
+		switch string(ctx.Path()) {
+		case "/missing":
+			ctx.SetStatusCode(fasthttp.StatusNotFound)
+		case "/down":
+			ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
+		}
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+	host := strings.TrimPrefix(addr, "http://")
+
+	metrics := NewHTTPMetrics(HTTPMetricsConfig{})
+	client := NewHTTPClient(WithBaseURL(addr), WithMetrics(metrics))
+	for _, path := range []string{"/ok", "/ok", "/missing", "/down"} {
+		_, _, err := client.GetJSON(path, nil)
+		require.NoError(t, err)
+	}
+	_, _, err = client.PostJSON("/ok", nil, map[string]string{"a": "b"})
+	require.NoError(t, err)
+
+	// Port yang sudah ditutup menghasilkan error jaringan.
+	deadAddr, closeDead, err := startTestServer(handler)
+	require.NoError(t, err)
+	closeDead()
+	_, _, err = NewHTTPClient(WithMetrics(metrics)).GetJSON(deadAddr, nil)
+	require.Error(t, err)
+	deadHost := strings.TrimPrefix(deadAddr, "http://")
+
+	var buf bytes.Buffer
+	require.NoError(t, metrics.WritePrometheus(&buf))
+	out := buf.String()
+
+	require.Contains(t, out, "# TYPE http_client_requests_total counter\n")
+	require.Contains(t, out, `http_client_requests_total{host="`+host+`",method="GET",status_class="2xx"} 2`)
+	require.Contains(t, out, `http_client_requests_total{host="`+host+`",method="GET",status_class="4xx"} 1`)
+	require.Contains(t, out, `http_client_requests_total{host="`+host+`",method="GET",status_class="5xx"} 1`)
+	require.Contains(t, out, `http_client_requests_total{host="`+host+`",method="POST",status_class="2xx"} 1`)
+	require.Contains(t, out, `http_client_requests_total{host="`+deadHost+`",method="GET",status_class="error"} 1`)
+
+	require.Contains(t, out, `http_client_request_errors_total{host="`+host+`",method="GET",status_class="5xx"} 1`)
+	require.Contains(t, out, `http_client_request_errors_total{host="`+deadHost+`",method="GET",status_class="error"} 1`)
+	require.NotContains(t, out, `http_client_request_errors_total{host="`+host+`",method="GET",status_class="4xx"}`)
+
+	require.Contains(t, out, "# TYPE http_client_request_duration_seconds histogram\n")
+	require.Contains(t, out, `http_client_request_duration_seconds_bucket{host="`+host+`",method="GET",status_class="2xx",le="+Inf"} 2`)
+	require.Contains(t, out, `http_client_request_duration_seconds_count{host="`+host+`",method="GET",status_class="2xx"} 2`)
+}
+
+func TestHTTPMetrics_HistogramAndExposition(t *testing.T) {
+	metrics := NewHTTPMetrics(HTTPMetricsConfig{Namespace: "payment", Buckets: []float64{0.5, 0.1}})
+	metrics.Observe(`bank"x`, "get", 200, 50*time.Millisecond)
+	metrics.Observe(`bank"x`, "get", 201, 300*time.Millisecond)
+	metrics.Observe(`bank"x`, "get", 204, 2*time.Second)
+
+	addr, closeServer, err := startTestServer(metrics.Handler())
+	require.NoError(t, err)
+	defer closeServer()
+
+	var resp fasthttp.Response
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	req.SetRequestURI(addr + "/metrics")
+	require.NoError(t, fasthttp.Do(req, &resp))
+	require.Equal(t, prometheusContentType, string(resp.Header.ContentType()))
+
+	labels := `host="bank\"x",method="GET",status_class="2xx"`
+	require.Equal(t, strings.Join([]string{
+		"# HELP payment_http_client_requests_total Total outbound HTTP requests.",
+		"# TYPE payment_http_client_requests_total counter",
+		"payment_http_client_requests_total{" + labels + "} 3",
+		"# HELP payment_http_client_request_errors_total Total outbound HTTP requests that failed with a network error or a 5xx response.",
+		"# TYPE payment_http_client_request_errors_total counter",
+		"# HELP payment_http_client_request_duration_seconds Outbound HTTP request latency in seconds.",
+		"# TYPE payment_http_client_request_duration_seconds histogram",
+		"payment_http_client_request_duration_seconds_bucket{" + labels + `,le="0.1"} 1`,
+		"payment_http_client_request_duration_seconds_bucket{" + labels + `,le="0.5"} 2`,
+		"payment_http_client_request_duration_seconds_bucket{" + labels + `,le="+Inf"} 3`,
+		"payment_http_client_request_duration_seconds_sum{" + labels + "} 2.35",
+		"payment_http_client_request_duration_seconds_count{" + labels + "} 3",
+		"",
+	}, "\n"), string(resp.Body()))
+
+	metrics.Reset()
+	var buf bytes.Buffer
+	require.NoError(t, metrics.WritePrometheus(&buf))
+	require.NotContains(t, buf.String(), "bank")
+}
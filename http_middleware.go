@@ -0,0 +1,127 @@
+package gocommon
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// HTTPHandler mengirim satu permintaan dan mengisi resp dengan respons dari server.
+type HTTPHandler func(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error
+
+// HTTPMiddleware membungkus HTTPHandler untuk menjalankan logika sebelum permintaan dikirim
+// (misalnya menambah header atau tanda tangan) dan sesudah respons diterima (misalnya logging atau metrik).
+//
+// Middleware dijalankan untuk setiap percobaan, sehingga saat retry aktif middleware terpanggil lebih dari sekali.
+//
+// Contoh middleware sederhana:
+//
+//	func AuthMiddleware(token string) HTTPMiddleware {
+//	    return func(next HTTPHandler) HTTPHandler {
+//	        return func(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+//	            req.Header.Set("Authorization", "Bearer "+token)
+//	            return next(ctx, req, resp)
+//	        }
+//	    }
+//	}
+type HTTPMiddleware func(next HTTPHandler) HTTPHandler
+
+var (
+	globalMiddlewaresMu sync.RWMutex
+	globalMiddlewares   []HTTPMiddleware
+)
+
+// UseHTTPMiddleware mendaftarkan middleware global yang berlaku untuk semua HTTPClient, termasuk
+// DefaultHTTPClient. Middleware global dijalankan sebelum (membungkus) middleware milik klien.
+func UseHTTPMiddleware(mw ...HTTPMiddleware) {
+	globalMiddlewaresMu.Lock()
+	defer globalMiddlewaresMu.Unlock()
+	globalMiddlewares = append(globalMiddlewares, mw...)
+}
+
+// ResetHTTPMiddleware menghapus semua middleware global.
+func ResetHTTPMiddleware() {
+	globalMiddlewaresMu.Lock()
+	defer globalMiddlewaresMu.Unlock()
+	globalMiddlewares = nil
+}
+
+// WithMiddleware menambahkan middleware pada klien. Middleware dijalankan sesuai urutan pendaftaran:
+// middleware pertama adalah yang paling luar.
+func WithMiddleware(mw ...HTTPMiddleware) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.middlewares = append(c.middlewares, mw...)
+	}
+}
+
+// ChainHTTPMiddleware menggabungkan beberapa middleware menjadi satu, dengan middleware pertama
+// sebagai yang paling luar.
+func ChainHTTPMiddleware(mw ...HTTPMiddleware) HTTPMiddleware {
+	return func(next HTTPHandler) HTTPHandler {
+		for i := len(mw) - 1; i >= 0; i-- {
+			next = mw[i](next)
+		}
+		return next
+	}
+}
+
+// handler membangun rantai middleware di sekitar pengiriman satu percobaan. Body dikompresi setelah
+// middleware agar middleware melihat body asli.
+func (c *HTTPClient) handler(ro *requestOptions) HTTPHandler {
+	return c.chain(func(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+		restore := c.compressRequestBody(req)
+		defer restore()
+		return c.doAttempt(ctx, req, resp, ro.timeout)
+	})
+}
+
+// chain membungkus terminal dengan middleware klien lalu middleware global.
+func (c *HTTPClient) chain(terminal HTTPHandler) HTTPHandler {
+	h := ChainHTTPMiddleware(c.middlewares...)(terminal)
+
+	globalMiddlewaresMu.RLock()
+	global := globalMiddlewares
+	globalMiddlewaresMu.RUnlock()
+	return ChainHTTPMiddleware(global...)(h)
+}
+
+// HeaderMiddleware mengembalikan middleware yang menambahkan header pada setiap permintaan.
+// Header yang sudah diatur pada permintaan akan ditimpa.
+func HeaderMiddleware(headers map[string]string) HTTPMiddleware {
+	return func(next HTTPHandler) HTTPHandler {
+		return func(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+			for k, v := range headers {
+				req.Header.Set(k, v)
+			}
+			return next(ctx, req, resp)
+		}
+	}
+}
+
+// LoggingMiddleware mengembalikan middleware yang mencatat metode, URL, status, dan durasi setiap permintaan.
+// Jika logf nil, log.Printf digunakan. Request ID dari context ikut dicatat jika ada.
+func LoggingMiddleware(logf func(format string, args ...interface{})) HTTPMiddleware {
+	if logf == nil {
+		logf = log.Printf
+	}
+	return func(next HTTPHandler) HTTPHandler {
+		return func(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+			start := time.Now()
+			err := next(ctx, req, resp)
+			elapsed := time.Since(start)
+
+			reqID := RequestIDFromContext(ctx)
+			if err != nil {
+				logf("http %s %s request_id=%q error=%v duration=%s",
+					req.Header.Method(), req.URI().String(), reqID, err, elapsed)
+				return err
+			}
+			logf("http %s %s request_id=%q status=%d duration=%s",
+				req.Header.Method(), req.URI().String(), reqID, resp.StatusCode(), elapsed)
+			return nil
+		}
+	}
+}
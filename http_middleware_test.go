@@ -0,0 +1,82 @@
+package gocommon
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestHTTPClient_MiddlewareOrder(t *testing.T) {
+	handler := func(ctx *fasthttp.RequestCtx) {
+		ctx.SetStatusCode(fasthttp.StatusOK)
+		ctx.SetBodyString(string(ctx.Request.Header.Peek("X-Trace")))
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	var order []string
+	tag := func(name string) HTTPMiddleware {
+		return func(next HTTPHandler) HTTPHandler {
+			return func(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+				order = append(order, "before:"+name)
+				req.Header.Set("X-Trace", string(req.Header.Peek("X-Trace"))+name)
+				err := next(ctx, req, resp)
+				order = append(order, "after:"+name)
+				return err
+			}
+		}
+	}
+
+	UseHTTPMiddleware(tag("g"))
+	t.Cleanup(ResetHTTPMiddleware)
+
+	client := NewHTTPClient(WithMiddleware(tag("a"), tag("b")))
+	resp, status, err := client.Request("GET", addr, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusOK, status)
+	require.Equal(t, "gab", string(resp))
+	require.Equal(t, []string{"before:g", "before:a", "before:b", "after:b", "after:a", "after:g"}, order)
+}
+
+func TestHeaderMiddleware(t *testing.T) {
+	handler := func(ctx *fasthttp.RequestCtx) {
+		ctx.SetStatusCode(fasthttp.StatusOK)
+		ctx.SetBodyString(string(ctx.Request.Header.Peek("Authorization")))
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	client := NewHTTPClient(WithMiddleware(HeaderMiddleware(map[string]string{"Authorization": "Bearer abc"})))
+	resp, _, err := client.GetJSON(addr, nil)
+	require.NoError(t, err)
+	require.Equal(t, "Bearer abc", string(resp))
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	handler := func(ctx *fasthttp.RequestCtx) {
+		ctx.SetStatusCode(fasthttp.StatusAccepted)
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	var lines []string
+	logf := func(format string, args ...interface{}) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	}
+	client := NewHTTPClient(WithMiddleware(LoggingMiddleware(logf)))
+
+	ctx := ContextWithRequestID(context.Background(), "req-9")
+	_, _, err = client.DoContext(ctx, "GET", addr+"/ping", nil, nil)
+	require.NoError(t, err)
+	require.Len(t, lines, 1)
+	require.True(t, strings.HasPrefix(lines[0], "http GET "+addr+"/ping"))
+	require.Contains(t, lines[0], `request_id="req-9"`)
+	require.Contains(t, lines[0], "status=202")
+}
@@ -0,0 +1,201 @@
+package gocommon
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// MultipartFile mendeskripsikan satu file yang akan dikirim dalam body multipart/form-data.
+// Isi file dibaca dari Reader, atau dari Path jika Reader nil. Isi file di-stream langsung ke
+// koneksi sehingga file besar tidak perlu dimuat seluruhnya ke memori.
+type MultipartFile struct {
+	// FieldName adalah nama field form, misalnya "ktp_photo".
+	FieldName string
+	// FileName adalah nama file yang dikirim ke server. Default: nama dasar dari Path.
+	FileName string
+	// ContentType adalah tipe konten part. Default: ditebak dari ekstensi FileName,
+	// atau "application/octet-stream".
+	ContentType string
+	// Reader adalah sumber isi file. Reader tidak ditutup oleh helper ini.
+	Reader io.Reader
+	// Size adalah ukuran isi file dalam byte. Jika 0, ukuran dideteksi dari Reader atau Path bila
+	// memungkinkan; jika tetap tidak diketahui, body dikirim dengan chunked transfer encoding.
+	Size int64
+	// Path adalah lokasi file di disk, dipakai jika Reader nil. File dibuka dan ditutup oleh helper ini.
+	Path string
+}
+
+// HTTPPostMultipart mengirim permintaan POST multipart/form-data berisi field teks dan file melalui
+// DefaultHTTPClient. Field dikirim berurutan menurut nama, diikuti file sesuai urutan slice.
+//
+// Karena body di-stream, permintaan multipart tidak di-retry.
+//
+// Contoh penggunaan:
+//
+//	resp, status, err := HTTPPostMultipart(url, nil,
+//	    map[string]string{"nik": "3171234567890001"},
+//	    []MultipartFile{{FieldName: "ktp_photo", Path: "/tmp/ktp.jpg"}},
+//	)
+func HTTPPostMultipart(url string, headers map[string]string, fields map[string]string, files []MultipartFile, opts ...RequestOption) ([]byte, int, error) {
+	return DefaultHTTPClient.PostMultipartContext(context.Background(), url, headers, fields, files, opts...)
+}
+
+// HTTPPostMultipartContext sama dengan HTTPPostMultipart, tetapi menghormati pembatalan dan deadline dari ctx.
+func HTTPPostMultipartContext(ctx context.Context, url string, headers map[string]string, fields map[string]string, files []MultipartFile, opts ...RequestOption) ([]byte, int, error) {
+	return DefaultHTTPClient.PostMultipartContext(ctx, url, headers, fields, files, opts...)
+}
+
+// PostMultipart mengirim permintaan POST multipart/form-data melalui klien ini.
+// Perilakunya sama dengan HTTPPostMultipart.
+func (c *HTTPClient) PostMultipart(url string, headers map[string]string, fields map[string]string, files []MultipartFile, opts ...RequestOption) ([]byte, int, error) {
+	return c.PostMultipartContext(context.Background(), url, headers, fields, files, opts...)
+}
+
+// PostMultipartContext sama dengan PostMultipart, tetapi menghormati pembatalan dan deadline dari ctx.
+func (c *HTTPClient) PostMultipartContext(ctx context.Context, url string, headers map[string]string, fields map[string]string, files []MultipartFile, opts ...RequestOption) ([]byte, int, error) {
+	body, contentType, size, closeFiles, err := buildMultipartBody(fields, files)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer closeFiles()
+
+	h := copyHeaders(headers)
+	h["Content-Type"] = contentType
+	opts = append(opts, withBodyStream(body, size))
+	return c.DoContext(ctx, "POST", url, h, nil, opts...)
+}
+
+// buildMultipartBody menyusun body multipart sebagai rangkaian reader tanpa menyalin isi file.
+// Nilai size bernilai -1 jika ukuran salah satu file tidak diketahui. closeFiles menutup file yang
+// dibuka dari Path; jika err tidak nil, file sudah ditutup dan closeFiles tetap aman dipanggil.
+func buildMultipartBody(fields map[string]string, files []MultipartFile) (body io.Reader, contentType string, size int, closeFiles func(), err error) {
+	var opened []*os.File
+	closeFiles = func() {
+		for _, f := range opened {
+			f.Close()
+		}
+	}
+	defer func() {
+		if err != nil {
+			closeFiles()
+		}
+	}()
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err = mw.WriteField(k, fields[k]); err != nil {
+			return nil, "", 0, closeFiles, err
+		}
+	}
+
+	var readers []io.Reader
+	total := int64(0)
+	known := true
+	flush := func() {
+		chunk := make([]byte, buf.Len())
+		copy(chunk, buf.Bytes())
+		buf.Reset()
+		readers = append(readers, bytes.NewReader(chunk))
+		total += int64(len(chunk))
+	}
+
+	for _, f := range files {
+		r, n := f.Reader, f.Size
+		name := f.FileName
+		if r == nil {
+			if f.Path == "" {
+				err = fmt.Errorf("multipart file %q has no reader or path", f.FieldName)
+				return nil, "", 0, closeFiles, err
+			}
+			var file *os.File
+			if file, err = os.Open(f.Path); err != nil {
+				return nil, "", 0, closeFiles, err
+			}
+			opened = append(opened, file)
+			r = file
+			if name == "" {
+				name = filepath.Base(f.Path)
+			}
+		}
+		if n <= 0 {
+			n = readerSize(r)
+		}
+
+		ct := f.ContentType
+		if ct == "" {
+			ct = mime.TypeByExtension(filepath.Ext(name))
+		}
+		if ct == "" {
+			ct = "application/octet-stream"
+		}
+
+		part := make(textproto.MIMEHeader)
+		part.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			escapeQuotes(f.FieldName), escapeQuotes(name)))
+		part.Set("Content-Type", ct)
+		if _, err = mw.CreatePart(part); err != nil {
+			return nil, "", 0, closeFiles, err
+		}
+		flush()
+
+		readers = append(readers, r)
+		if n < 0 {
+			known = false
+		} else {
+			total += n
+		}
+	}
+
+	if err = mw.Close(); err != nil {
+		return nil, "", 0, closeFiles, err
+	}
+	flush()
+
+	size = -1
+	if known {
+		size = int(total)
+	}
+	return io.MultiReader(readers...), mw.FormDataContentType(), size, closeFiles, nil
+}
+
+// readerSize mencoba mengetahui sisa ukuran isi reader, atau -1 jika tidak diketahui.
+func readerSize(r io.Reader) int64 {
+	switch v := r.(type) {
+	case interface{ Len() int }:
+		return int64(v.Len())
+	case *os.File:
+		info, err := v.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return -1
+		}
+		pos, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		return info.Size() - pos
+	}
+	return -1
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// escapeQuotes meng-escape karakter kutip pada nilai Content-Disposition, sama seperti mime/multipart.
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
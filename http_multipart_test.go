@@ -0,0 +1,110 @@
+package gocommon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// multipartEchoHandler mem-parse form multipart dan mengembalikan ringkasannya sebagai JSON.
+func multipartEchoHandler(ctx *fasthttp.RequestCtx) {
+	form, err := ctx.MultipartForm()
+	if err != nil {
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetBodyString(err.Error())
+		return
+	}
+	out := map[string]string{
+		"chunked": string(ctx.Request.Header.Peek("Transfer-Encoding")),
+	}
+	for k, v := range form.Value {
+		out["field:"+k] = v[0]
+	}
+	for k, fhs := range form.File {
+		f, _ := fhs[0].Open()
+		b, _ := io.ReadAll(f)
+		f.Close()
+		out["file:"+k] = fhs[0].Filename + "|" + fhs[0].Header.Get("Content-Type") + "|" + string(b)
+	}
+	body, _ := json.Marshal(out)
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	ctx.SetBody(body)
+}
+
+func TestHTTPPostMultipart_FieldsAndFiles(t *testing.T) {
+	addr, closeServer, err := startTestServer(multipartEchoHandler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	path := filepath.Join(t.TempDir(), "ktp.jpg")
+	require.NoError(t, os.WriteFile(path, []byte("jpeg-bytes"), 0o600))
+
+	resp, status, err := HTTPPostMultipart(addr, nil,
+		map[string]string{"nik": "3171234567890001", "name": "Budi"},
+		[]MultipartFile{
+			{FieldName: "ktp_photo", Path: path},
+			{FieldName: "doc", FileName: "surat.pdf", Reader: bytes.NewReader([]byte("%PDF"))},
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusOK, status, string(resp))
+
+	var out map[string]string
+	require.NoError(t, json.Unmarshal(resp, &out))
+	require.Equal(t, "3171234567890001", out["field:nik"])
+	require.Equal(t, "Budi", out["field:name"])
+	require.Equal(t, "ktp.jpg|image/jpeg|jpeg-bytes", out["file:ktp_photo"])
+	require.Equal(t, "surat.pdf|application/pdf|%PDF", out["file:doc"])
+	require.Empty(t, out["chunked"])
+}
+
+func TestHTTPClient_PostMultipart_UnknownSizeIsChunked(t *testing.T) {
+	addr, closeServer, err := startTestServer(multipartEchoHandler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	big := strings.Repeat("a", 256*1024)
+	resp, status, err := NewHTTPClient().PostMultipart(addr, nil, nil, []MultipartFile{
+		{FieldName: "report", FileName: "report.bin", Reader: io.LimitReader(strings.NewReader(big), int64(len(big)))},
+	})
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusOK, status, string(resp))
+
+	var out map[string]string
+	require.NoError(t, json.Unmarshal(resp, &out))
+	require.Equal(t, "chunked", out["chunked"])
+	require.Equal(t, "report.bin|application/octet-stream|"+big, out["file:report"])
+}
+
+func TestHTTPPostMultipart_MissingSource(t *testing.T) {
+	_, _, err := HTTPPostMultipart("http://127.0.0.1:1", nil, nil, []MultipartFile{{FieldName: "x"}})
+	require.Error(t, err)
+
+	_, _, err = HTTPPostMultipart("http://127.0.0.1:1", nil, nil, []MultipartFile{{FieldName: "x", Path: "/does/not/exist"}})
+	require.Error(t, err)
+}
+
+func TestHTTPPostMultipartContext_StreamsWithCancelableContext(t *testing.T) {
+	addr, closeServer, err := startTestServer(multipartEchoHandler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	resp, status, err := HTTPPostMultipartContext(ctx, addr, nil, map[string]string{"a": "1"}, []MultipartFile{
+		{FieldName: "f", FileName: "f.txt", Reader: strings.NewReader("hello")},
+	})
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusOK, status, string(resp))
+	require.Contains(t, string(resp), `"file:f":"f.txt|text/plain; charset=utf-8|hello"`)
+}
@@ -0,0 +1,296 @@
+package gocommon
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+const defaultTokenExpiryDelta = 30 * time.Second
+
+// OAuth2AuthStyle menentukan cara client ID dan client secret dikirim ke token endpoint.
+type OAuth2AuthStyle int
+
+const (
+	// OAuth2AuthHeader mengirim kredensial melalui header Authorization Basic (RFC 6749 bagian 2.3.1).
+	OAuth2AuthHeader OAuth2AuthStyle = iota
+	// OAuth2AuthParams mengirim kredensial sebagai parameter client_id dan client_secret di body form.
+	OAuth2AuthParams
+)
+
+// Token adalah access token OAuth2 beserta waktu kedaluwarsanya.
+type Token struct {
+	AccessToken string
+	// TokenType biasanya "Bearer".
+	TokenType string
+	// Expiry adalah waktu token kedaluwarsa. Nilai nol berarti token tidak pernah kedaluwarsa.
+	Expiry time.Time
+	// Scope adalah scope yang diberikan server, jika dikirim.
+	Scope string
+}
+
+// AuthorizationHeader mengembalikan nilai header Authorization untuk token, misalnya "Bearer abc".
+func (t *Token) AuthorizationHeader() string {
+	tokenType := t.TokenType
+	if tokenType == "" || strings.EqualFold(tokenType, "bearer") {
+		tokenType = "Bearer"
+	}
+	return tokenType + " " + t.AccessToken
+}
+
+// validFor melaporkan apakah token masih berlaku setidaknya selama delta.
+func (t *Token) validFor(delta time.Duration) bool {
+	if t == nil || t.AccessToken == "" {
+		return false
+	}
+	return t.Expiry.IsZero() || time.Now().Add(delta).Before(t.Expiry)
+}
+
+// TokenSource menyediakan access token untuk permintaan keluar.
+type TokenSource interface {
+	Token(ctx context.Context) (*Token, error)
+}
+
+// OAuth2Error adalah error dari token endpoint, berisi kode error OAuth2 (RFC 6749 bagian 5.2).
+type OAuth2Error struct {
+	StatusCode  int
+	Code        string
+	Description string
+	// Body adalah potongan body respons, diisi jika body bukan respons error OAuth2 standar.
+	Body []byte
+}
+
+// Error mengimplementasikan interface error.
+func (e *OAuth2Error) Error() string {
+	switch {
+	case e.Code != "" && e.Description != "":
+		return fmt.Sprintf("oauth2: token request failed with status %d: %s: %s", e.StatusCode, e.Code, e.Description)
+	case e.Code != "":
+		return fmt.Sprintf("oauth2: token request failed with status %d: %s", e.StatusCode, e.Code)
+	}
+	return fmt.Sprintf("oauth2: token request failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+// ClientCredentialsConfig berisi pengaturan grant OAuth2 client credentials.
+type ClientCredentialsConfig struct {
+	// TokenURL adalah URL token endpoint.
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	// Scopes dikirim sebagai parameter scope yang dipisahkan spasi.
+	Scopes []string
+	// EndpointParams berisi parameter tambahan untuk token endpoint, misalnya audience.
+	EndpointParams url.Values
+	// AuthStyle menentukan cara kredensial dikirim. Default OAuth2AuthHeader.
+	AuthStyle OAuth2AuthStyle
+	// ExpiryDelta adalah jarak sebelum kedaluwarsa ketika token dianggap perlu di-refresh. Default 30 detik.
+	ExpiryDelta time.Duration
+	// Client adalah klien yang dipakai untuk memanggil token endpoint. Default DefaultHTTPClient.
+	// OAuth2Middleware, termasuk yang didaftarkan lewat UseHTTPMiddleware, dilewati pada permintaan ini.
+	Client *HTTPClient
+}
+
+// ClientCredentialsTokenSource mengambil token dengan grant client credentials dan menyimpannya
+// sampai sesaat sebelum kedaluwarsa. Jika banyak goroutine meminta token bersamaan saat cache kosong,
+// hanya satu permintaan ke token endpoint yang dikirim dan hasilnya dibagi ke semua pemanggil.
+type ClientCredentialsTokenSource struct {
+	cfg ClientCredentialsConfig
+
+	mu    sync.Mutex
+	token *Token
+	call  *tokenCall
+}
+
+// tokenCall mewakili satu pengambilan token yang sedang berjalan.
+type tokenCall struct {
+	done  chan struct{}
+	token *Token
+	err   error
+}
+
+// NewClientCredentialsTokenSource membuat token source untuk grant client credentials.
+//
+// Contoh penggunaan:
+//
+//	ts := NewClientCredentialsTokenSource(ClientCredentialsConfig{
+//	    TokenURL:     "https://auth.partner.com/oauth/token",
+//	    ClientID:     os.Getenv("PARTNER_CLIENT_ID"),
+//	    ClientSecret: os.Getenv("PARTNER_CLIENT_SECRET"),
+//	    Scopes:       []string{"payments"},
+//	})
+//	client := NewHTTPClient(WithBaseURL("https://api.partner.com"), WithTokenSource(ts))
+func NewClientCredentialsTokenSource(cfg ClientCredentialsConfig) *ClientCredentialsTokenSource {
+	if cfg.ExpiryDelta <= 0 {
+		cfg.ExpiryDelta = defaultTokenExpiryDelta
+	}
+	return &ClientCredentialsTokenSource{cfg: cfg}
+}
+
+// Token mengembalikan token dari cache, atau mengambil token baru jika cache kosong atau hampir
+// kedaluwarsa. Pembatalan ctx hanya menghentikan penantian pemanggil ini; pengambilan token yang
+// sedang berjalan tetap diselesaikan untuk pemanggil lain.
+func (s *ClientCredentialsTokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	if s.token.validFor(s.cfg.ExpiryDelta) {
+		t := s.token
+		s.mu.Unlock()
+		return t, nil
+	}
+	call := s.call
+	if call == nil {
+		call = &tokenCall{done: make(chan struct{})}
+		s.call = call
+		go s.fetch(context.WithoutCancel(ctx), call)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.token, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Invalidate menghapus token dari cache jika token tersebut masih yang tersimpan, misalnya setelah
+// server menolaknya dengan 401. Token yang sudah diganti oleh goroutine lain tidak ikut dihapus.
+func (s *ClientCredentialsTokenSource) Invalidate(t *Token) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != nil && t != nil && s.token.AccessToken == t.AccessToken {
+		s.token = nil
+	}
+}
+
+// fetch mengambil token baru dan menyimpannya ke cache.
+func (s *ClientCredentialsTokenSource) fetch(ctx context.Context, call *tokenCall) {
+	call.token, call.err = s.requestToken(ctx)
+
+	s.mu.Lock()
+	if call.err == nil {
+		s.token = call.token
+	}
+	s.call = nil
+	s.mu.Unlock()
+	close(call.done)
+}
+
+// requestToken memanggil token endpoint.
+func (s *ClientCredentialsTokenSource) requestToken(ctx context.Context) (*Token, error) {
+	form := url.Values{}
+	for k, v := range s.cfg.EndpointParams {
+		form[k] = append([]string(nil), v...)
+	}
+	form.Set("grant_type", "client_credentials")
+	if len(s.cfg.Scopes) > 0 {
+		form.Set("scope", strings.Join(s.cfg.Scopes, " "))
+	}
+
+	headers := map[string]string{"Accept": "application/json"}
+	if s.cfg.AuthStyle == OAuth2AuthParams {
+		form.Set("client_id", s.cfg.ClientID)
+		form.Set("client_secret", s.cfg.ClientSecret)
+	} else {
+		cred := url.QueryEscape(s.cfg.ClientID) + ":" + url.QueryEscape(s.cfg.ClientSecret)
+		headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(cred))
+	}
+
+	client := s.cfg.Client
+	if client == nil {
+		client = DefaultHTTPClient
+	}
+	body, status, err := client.PostFormContext(context.WithValue(ctx, tokenRequestKey{}, true), s.cfg.TokenURL, headers, form,
+		WithStatusError(false), WithJSONValidation(false))
+	if err != nil {
+		return nil, fmt.Errorf("oauth2: token request: %w", err)
+	}
+
+	var tr struct {
+		AccessToken      string      `json:"access_token"`
+		TokenType        string      `json:"token_type"`
+		ExpiresIn        json.Number `json:"expires_in"`
+		Scope            string      `json:"scope"`
+		Error            string      `json:"error"`
+		ErrorDescription string      `json:"error_description"`
+	}
+	decodeErr := json.Unmarshal(body, &tr)
+	if status < 200 || status > 299 || decodeErr != nil || tr.AccessToken == "" {
+		oerr := &OAuth2Error{StatusCode: status, Code: tr.Error, Description: tr.ErrorDescription}
+		if oerr.Code == "" {
+			oerr.Body = truncateBody(body, httpErrorBodyLimit)
+		}
+		return nil, oerr
+	}
+
+	t := &Token{AccessToken: tr.AccessToken, TokenType: tr.TokenType, Scope: tr.Scope}
+	if secs, err := tr.ExpiresIn.Int64(); err == nil && secs > 0 {
+		t.Expiry = time.Now().Add(time.Duration(secs) * time.Second)
+	}
+	return t, nil
+}
+
+// tokenRequestKey menandai context permintaan ke token endpoint agar OAuth2Middleware tidak
+// mengambil token lagi untuk permintaan tersebut, yang akan menunggu dirinya sendiri.
+type tokenRequestKey struct{}
+
+// tokenInvalidator diimplementasikan token source yang dapat membuang token yang ditolak server.
+type tokenInvalidator interface {
+	Invalidate(t *Token)
+}
+
+// OAuth2Middleware memasang header Authorization dari ts pada setiap permintaan. Jika server membalas
+// 401 dan ts mendukung Invalidate (seperti ClientCredentialsTokenSource), token dibuang lalu permintaan
+// diulang satu kali dengan token baru. Permintaan dengan body stream tidak diulang karena body sudah
+// terbaca habis.
+//
+// Middleware ini aman didaftarkan secara global:
+//
+//	UseHTTPMiddleware(OAuth2Middleware(ts))
+//
+// Permintaan token milik ClientCredentialsTokenSource tidak diberi header Authorization olehnya.
+func OAuth2Middleware(ts TokenSource) HTTPMiddleware {
+	return func(next HTTPHandler) HTTPHandler {
+		return func(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+			if ctx.Value(tokenRequestKey{}) != nil {
+				return next(ctx, req, resp)
+			}
+			t, err := ts.Token(ctx)
+			if err != nil {
+				return err
+			}
+			// Dibaca sebelum next karena fasthttp menutup body stream setelah permintaan dikirim.
+			stream := req.IsBodyStream()
+			req.Header.Set("Authorization", t.AuthorizationHeader())
+			if err := next(ctx, req, resp); err != nil {
+				return err
+			}
+
+			inv, ok := ts.(tokenInvalidator)
+			if !ok || resp.StatusCode() != fasthttp.StatusUnauthorized || stream {
+				return nil
+			}
+			inv.Invalidate(t)
+			t, err = ts.Token(ctx)
+			if err != nil {
+				return err
+			}
+			req.Header.Set("Authorization", t.AuthorizationHeader())
+			resp.Reset()
+			return next(ctx, req, resp)
+		}
+	}
+}
+
+// WithTokenSource memasang OAuth2Middleware(ts) pada klien sehingga semua permintaan otomatis
+// membawa header Authorization.
+func WithTokenSource(ts TokenSource) HTTPClientOption {
+	return WithMiddleware(OAuth2Middleware(ts))
+}
@@ -0,0 +1,204 @@
+package gocommon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestClientCredentialsTokenSource_CachesAndSingleflight(t *testing.T) {
+	var tokenCalls int32
+	handler := func(ctx *fasthttp.RequestCtx) {
+		require.Equal(t, "client_credentials", string(ctx.PostArgs().Peek("grant_type")))
+		require.Equal(t, "read write", string(ctx.PostArgs().Peek("scope")))
+		user, pass, ok := parseBasicAuth(string(ctx.Request.Header.Peek("Authorization")))
+		if !ok || user != "id" || pass != "secret" {
+			ctx.SetStatusCode(fasthttp.StatusUnauthorized)
+			ctx.SetBodyString(`{"error":"invalid_client","error_description":"bad credentials"}`)
+			return
+		}
+		n := atomic.AddInt32(&tokenCalls, 1)
+		time.Sleep(20 * time.Millisecond)
+		ctx.SetContentType("application/json")
+		fmt.Fprintf(ctx, `{"access_token":"tok-%d","token_type":"bearer","expires_in":3600}`, n)
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	ts := NewClientCredentialsTokenSource(ClientCredentialsConfig{
+		TokenURL:     addr + "/token",
+		ClientID:     "id",
+		ClientSecret: "secret",
+		Scopes:       []string{"read", "write"},
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tok, err := ts.Token(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, "tok-1", tok.AccessToken)
+		}()
+	}
+	wg.Wait()
+	require.EqualValues(t, 1, atomic.LoadInt32(&tokenCalls))
+
+	tok, err := ts.Token(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "Bearer tok-1", tok.AuthorizationHeader())
+	require.WithinDuration(t, time.Now().Add(time.Hour), tok.Expiry, 5*time.Second)
+
+	ts.Invalidate(&Token{AccessToken: "stale"})
+	tok, _ = ts.Token(context.Background())
+	require.Equal(t, "tok-1", tok.AccessToken)
+
+	ts.Invalidate(tok)
+	tok, _ = ts.Token(context.Background())
+	require.Equal(t, "tok-2", tok.AccessToken)
+
+	bad := NewClientCredentialsTokenSource(ClientCredentialsConfig{
+		TokenURL:     addr + "/token",
+		ClientID:     "id",
+		ClientSecret: "wrong",
+		Scopes:       []string{"read", "write"},
+	})
+	_, err = bad.Token(context.Background())
+	var oerr *OAuth2Error
+	require.ErrorAs(t, err, &oerr)
+	require.Equal(t, "invalid_client", oerr.Code)
+	require.Equal(t, fasthttp.StatusUnauthorized, oerr.StatusCode)
+}
+
+func TestClientCredentialsTokenSource_RefreshesBeforeExpiry(t *testing.T) {
+	var tokenCalls int32
+	handler := func(ctx *fasthttp.RequestCtx) {
+		n := atomic.AddInt32(&tokenCalls, 1)
+		require.Equal(t, "id", string(ctx.PostArgs().Peek("client_id")))
+		fmt.Fprintf(ctx, `{"access_token":"tok-%d","expires_in":"40"}`, n)
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	ts := NewClientCredentialsTokenSource(ClientCredentialsConfig{
+		TokenURL:     addr,
+		ClientID:     "id",
+		ClientSecret: "secret",
+		AuthStyle:    OAuth2AuthParams,
+		ExpiryDelta:  time.Minute,
+	})
+	for i := 1; i <= 2; i++ {
+		tok, err := ts.Token(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprintf("tok-%d", i), tok.AccessToken)
+	}
+}
+
+func TestOAuth2Middleware_RetriesOnceOn401(t *testing.T) {
+	var tokenCalls, apiCalls int32
+	handler := func(ctx *fasthttp.RequestCtx) {
+		switch string(ctx.Path()) {
+		case "/token":
+			n := atomic.AddInt32(&tokenCalls, 1)
+			fmt.Fprintf(ctx, `{"access_token":"tok-%d","token_type":"Bearer","expires_in":3600}`, n)
+		case "/api":
+			atomic.AddInt32(&apiCalls, 1)
+			// Only the second token is accepted, simulating a revoked first token.
+			if string(ctx.Request.Header.Peek("Authorization")) != "Bearer tok-2" {
+				ctx.SetStatusCode(fasthttp.StatusUnauthorized)
+				return
+			}
+			ctx.SetBodyString(`{"ok":true}`)
+		case "/always401":
+			atomic.AddInt32(&apiCalls, 1)
+			ctx.SetStatusCode(fasthttp.StatusUnauthorized)
+		}
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	ts := NewClientCredentialsTokenSource(ClientCredentialsConfig{TokenURL: addr + "/token", ClientID: "id", ClientSecret: "secret"})
+	client := NewHTTPClient(WithBaseURL(addr), WithTokenSource(ts))
+
+	resp, status, err := client.PostJSON("/api", nil, map[string]string{"a": "b"})
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusOK, status)
+	require.JSONEq(t, `{"ok":true}`, string(resp))
+	require.EqualValues(t, 2, atomic.LoadInt32(&tokenCalls))
+	require.EqualValues(t, 2, atomic.LoadInt32(&apiCalls))
+
+	atomic.StoreInt32(&apiCalls, 0)
+	_, status, err = client.GetJSON("/always401", nil)
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusUnauthorized, status)
+	require.EqualValues(t, 2, atomic.LoadInt32(&apiCalls))
+}
+
+func TestOAuth2Middleware_DoesNotRetryStreamedBody(t *testing.T) {
+	var apiCalls int32
+	handler := func(ctx *fasthttp.RequestCtx) {
+		if string(ctx.Path()) == "/token" {
+			ctx.SetBodyString(`{"access_token":"tok","expires_in":3600}`)
+			return
+		}
+		atomic.AddInt32(&apiCalls, 1)
+		ctx.SetStatusCode(fasthttp.StatusUnauthorized)
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	ts := NewClientCredentialsTokenSource(ClientCredentialsConfig{TokenURL: addr + "/token", ClientID: "id", ClientSecret: "secret"})
+	client := NewHTTPClient(WithBaseURL(addr), WithTokenSource(ts))
+	_, status, err := client.PostMultipart("/upload", nil, map[string]string{"nik": "3171234567890001"},
+		[]MultipartFile{{FieldName: "ktp", FileName: "ktp.jpg", Reader: strings.NewReader("jpeg-bytes")}})
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusUnauthorized, status)
+	require.EqualValues(t, 1, atomic.LoadInt32(&apiCalls))
+}
+
+func TestOAuth2Middleware_GlobalMiddleware(t *testing.T) {
+	var tokenAuth atomic.Value
+	handler := func(ctx *fasthttp.RequestCtx) {
+		if string(ctx.Path()) == "/token" {
+			tokenAuth.Store(string(ctx.Request.Header.Peek("Authorization")))
+			ctx.SetBodyString(`{"access_token":"tok","expires_in":3600}`)
+			return
+		}
+		ctx.SetBodyString(`{"auth":"` + string(ctx.Request.Header.Peek("Authorization")) + `"}`)
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	ts := NewClientCredentialsTokenSource(ClientCredentialsConfig{TokenURL: addr + "/token", ClientID: "id", ClientSecret: "secret"})
+	UseHTTPMiddleware(OAuth2Middleware(ts))
+	t.Cleanup(ResetHTTPMiddleware)
+
+	// Permintaan token melewati DefaultHTTPClient yang juga memakai middleware global ini.
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	resp, status, err := HTTPGetJSONContext(ctx, addr+"/api", nil)
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusOK, status)
+	require.JSONEq(t, `{"auth":"Bearer tok"}`, string(resp))
+	require.True(t, strings.HasPrefix(tokenAuth.Load().(string), "Basic "))
+}
+
+// parseBasicAuth memecah header Authorization Basic menjadi username dan password.
+func parseBasicAuth(header string) (user, pass string, ok bool) {
+	r := &http.Request{Header: http.Header{"Authorization": {header}}}
+	return r.BasicAuth()
+}
@@ -0,0 +1,411 @@
+package gocommon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Page adalah satu halaman hasil PageFetcher.
+type Page[T any] struct {
+	Items []T
+	// Next adalah penanda halaman berikutnya (nomor halaman, cursor, atau URL) yang diteruskan ke
+	// pemanggilan PageFetcher berikutnya. String kosong berarti tidak ada halaman lagi.
+	Next string
+}
+
+// PageFetcher mengambil satu halaman. Parameter next bernilai kosong untuk halaman pertama, lalu
+// berisi Page.Next dari halaman sebelumnya. Strategi bawaan: OffsetPages, CursorPages, dan LinkPages.
+type PageFetcher[T any] func(ctx context.Context, next string) (Page[T], error)
+
+// Paginate mengembalikan iterator yang mengambil halaman secara lazy dan menghasilkan item satu per
+// satu. Halaman berikutnya baru diambil setelah semua item halaman sebelumnya dikonsumsi. Iterasi
+// berhenti ketika halaman terakhir tercapai, loop dihentikan dengan break, atau terjadi error.
+// Error (termasuk ctx.Err() saat ctx dibatalkan) dihasilkan sebagai elemen terakhir.
+//
+// Contoh penggunaan:
+//
+//	pages := OffsetPages[Bank](PageRequest{URL: "https://api.partner.co.id/v1/banks", ItemsField: "data"}, OffsetPaging{Limit: 100})
+//	for bank, err := range Paginate(ctx, pages) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    fmt.Println(bank.Code)
+//	}
+func Paginate[T any](ctx context.Context, fetch PageFetcher[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		next := ""
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+			page, err := fetch(ctx, next)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, item := range page.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			// Penanda yang sama dengan sebelumnya berarti server mengulang halaman; hentikan agar tidak loop.
+			if page.Next == "" || page.Next == next {
+				return
+			}
+			next = page.Next
+		}
+	}
+}
+
+// PaginateAll mengumpulkan semua item dari Paginate ke dalam satu slice.
+// Jika terjadi error, item yang sudah terkumpul tetap dikembalikan bersama error tersebut.
+func PaginateAll[T any](ctx context.Context, fetch PageFetcher[T]) ([]T, error) {
+	var items []T
+	for item, err := range Paginate(ctx, fetch) {
+		if err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// PageRequest berisi pengaturan permintaan GET yang dipakai strategi paginasi bawaan.
+type PageRequest struct {
+	// Client adalah klien yang dipakai. Default DefaultHTTPClient.
+	Client *HTTPClient
+	// URL adalah URL halaman pertama; boleh relatif terhadap base URL klien.
+	URL     string
+	Headers map[string]string
+	Options []RequestOption
+	// ItemsField adalah path field JSON berisi array item, dipisah titik untuk objek bersarang,
+	// misalnya "data" atau "result.items". Kosong berarti body respons adalah array.
+	ItemsField string
+}
+
+// OffsetPaging berisi pengaturan OffsetPages.
+type OffsetPaging struct {
+	// PageParam adalah nama parameter query nomor halaman. Default "page".
+	PageParam string
+	// LimitParam adalah nama parameter query ukuran halaman. Default "limit".
+	LimitParam string
+	// Limit adalah jumlah item per halaman. Default 100.
+	Limit int
+	// FirstPage adalah nomor halaman pertama. Default 1; gunakan ZeroBased untuk API yang dimulai dari 0.
+	FirstPage int
+	// ZeroBased, jika true, memulai nomor halaman dari 0 dan mengabaikan FirstPage.
+	ZeroBased bool
+	// OffsetParam, jika diisi, mengganti PageParam dengan parameter offset berbasis item. Offset
+	// berikutnya dihitung dari jumlah item yang benar-benar diterima, bukan dari Limit.
+	OffsetParam string
+	// HasMoreField, jika diisi, adalah path field JSON boolean yang menandai masih ada halaman
+	// berikutnya, dipisah titik, misalnya "meta.has_more".
+	HasMoreField string
+}
+
+// OffsetPages membuat PageFetcher untuk API berbasis page/limit (atau offset/limit). Paginasi berhenti
+// ketika sebuah halaman kosong, atau ketika HasMoreField bernilai false. Halaman yang berisi kurang dari
+// Limit item tidak dianggap halaman terakhir karena banyak server membatasi ukuran halaman di bawah
+// nilai yang diminta; tanpa HasMoreField, satu permintaan tambahan dikirim untuk memastikan halaman kosong.
+func OffsetPages[T any](r PageRequest, cfg OffsetPaging) PageFetcher[T] {
+	if cfg.PageParam == "" {
+		cfg.PageParam = "page"
+	}
+	if cfg.LimitParam == "" {
+		cfg.LimitParam = "limit"
+	}
+	if cfg.Limit <= 0 {
+		cfg.Limit = 100
+	}
+	switch {
+	case cfg.ZeroBased:
+		cfg.FirstPage = 0
+	case cfg.FirstPage == 0:
+		cfg.FirstPage = 1
+	}
+
+	// Token halaman berisi nomor halaman, atau offset item jika OffsetParam diisi.
+	return func(ctx context.Context, next string) (Page[T], error) {
+		pos := cfg.FirstPage
+		if cfg.OffsetParam != "" {
+			pos = 0
+		}
+		if next != "" {
+			n, err := strconv.Atoi(next)
+			if err != nil {
+				return Page[T]{}, fmt.Errorf("invalid page token %q", next)
+			}
+			pos = n
+		}
+		params := map[string]string{cfg.LimitParam: strconv.Itoa(cfg.Limit)}
+		if cfg.OffsetParam != "" {
+			params[cfg.OffsetParam] = strconv.Itoa(pos)
+		} else {
+			params[cfg.PageParam] = strconv.Itoa(pos)
+		}
+		pageURL, err := withQueryParams(r.URL, params)
+		if err != nil {
+			return Page[T]{}, err
+		}
+
+		res, err := fetchPage(ctx, r, pageURL)
+		if err != nil {
+			return Page[T]{}, err
+		}
+		items, err := decodePageItems[T](res.body, r.ItemsField)
+		if err != nil {
+			return Page[T]{}, err
+		}
+		result := Page[T]{Items: items}
+		if len(items) == 0 {
+			return result, nil
+		}
+		if cfg.HasMoreField != "" {
+			raw, err := jsonField(res.body, cfg.HasMoreField)
+			if err != nil {
+				return Page[T]{}, err
+			}
+			var more bool
+			if raw != nil {
+				if err := json.Unmarshal(raw, &more); err != nil {
+					return Page[T]{}, fmt.Errorf("decode page field %q: expected boolean", cfg.HasMoreField)
+				}
+			}
+			if !more {
+				return result, nil
+			}
+		}
+		if cfg.OffsetParam != "" {
+			result.Next = strconv.Itoa(pos + len(items))
+		} else {
+			result.Next = strconv.Itoa(pos + 1)
+		}
+		return result, nil
+	}
+}
+
+// CursorPaging berisi pengaturan CursorPages.
+type CursorPaging struct {
+	// CursorParam adalah nama parameter query cursor. Default "cursor".
+	CursorParam string
+	// NextCursorField adalah path field JSON berisi cursor halaman berikutnya, dipisah titik,
+	// misalnya "meta.next_cursor". Default "next_cursor".
+	NextCursorField string
+	// LimitParam dan Limit, jika diisi, menambahkan parameter ukuran halaman pada setiap permintaan.
+	LimitParam string
+	Limit      int
+}
+
+// CursorPages membuat PageFetcher untuk API berbasis cursor. Paginasi berhenti ketika field cursor
+// berikutnya kosong, null, atau tidak ada.
+func CursorPages[T any](r PageRequest, cfg CursorPaging) PageFetcher[T] {
+	if cfg.CursorParam == "" {
+		cfg.CursorParam = "cursor"
+	}
+	if cfg.NextCursorField == "" {
+		cfg.NextCursorField = "next_cursor"
+	}
+
+	return func(ctx context.Context, next string) (Page[T], error) {
+		params := map[string]string{}
+		if next != "" {
+			params[cfg.CursorParam] = next
+		}
+		if cfg.LimitParam != "" && cfg.Limit > 0 {
+			params[cfg.LimitParam] = strconv.Itoa(cfg.Limit)
+		}
+		pageURL, err := withQueryParams(r.URL, params)
+		if err != nil {
+			return Page[T]{}, err
+		}
+
+		res, err := fetchPage(ctx, r, pageURL)
+		if err != nil {
+			return Page[T]{}, err
+		}
+		items, err := decodePageItems[T](res.body, r.ItemsField)
+		if err != nil {
+			return Page[T]{}, err
+		}
+		cursor, err := jsonFieldString(res.body, cfg.NextCursorField)
+		if err != nil {
+			return Page[T]{}, err
+		}
+		return Page[T]{Items: items, Next: cursor}, nil
+	}
+}
+
+// LinkPages membuat PageFetcher untuk API yang menandai halaman berikutnya dengan header
+// Link rel="next" (RFC 5988), seperti API GitHub. URL relatif pada header di-resolve terhadap URL halaman.
+func LinkPages[T any](r PageRequest) PageFetcher[T] {
+	return func(ctx context.Context, next string) (Page[T], error) {
+		pageURL := r.URL
+		if next != "" {
+			pageURL = next
+		}
+		res, err := fetchPage(ctx, r, pageURL)
+		if err != nil {
+			return Page[T]{}, err
+		}
+		items, err := decodePageItems[T](res.body, r.ItemsField)
+		if err != nil {
+			return Page[T]{}, err
+		}
+
+		result := Page[T]{Items: items}
+		for _, value := range res.header.Values("Link") {
+			if link, ok := parseLinkHeader(value)["next"]; ok {
+				result.Next = resolveReference(res.url, link)
+				break
+			}
+		}
+		return result, nil
+	}
+}
+
+// fetchPage mengirim permintaan GET untuk satu halaman. Respons non-2xx dikembalikan sebagai *HTTPError.
+func fetchPage(ctx context.Context, r PageRequest, pageURL string) (*httpResult, error) {
+	c := r.Client
+	if c == nil {
+		c = DefaultHTTPClient
+	}
+	h := copyHeaders(r.Headers)
+	h["Accept"] = "application/json"
+	ro := c.newRequestOptions(r.Options)
+	ro.statusError = false
+
+	res, err := c.execute(ctx, "GET", pageURL, h, nil, ro)
+	if err != nil {
+		return nil, err
+	}
+	if res.status < 200 || res.status > 299 {
+		_, err := decodeJSONResult[json.RawMessage](res, ro)
+		return nil, err
+	}
+	return res, nil
+}
+
+// decodePageItems men-decode array item dari field path pada body.
+func decodePageItems[T any](body []byte, path string) ([]T, error) {
+	raw, err := jsonField(body, path)
+	if err != nil || raw == nil {
+		return nil, err
+	}
+	var items []T
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, fmt.Errorf("decode page items: %w", err)
+	}
+	return items, nil
+}
+
+// jsonField mengambil nilai mentah field path (dipisah titik) dari body. Nilai nil dikembalikan
+// jika field tidak ada atau bernilai null.
+func jsonField(body []byte, path string) (json.RawMessage, error) {
+	raw := json.RawMessage(bytes.TrimSpace(body))
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	if path != "" {
+		for _, key := range strings.Split(path, ".") {
+			var obj map[string]json.RawMessage
+			if err := json.Unmarshal(raw, &obj); err != nil {
+				return nil, fmt.Errorf("decode page field %q: %w", path, err)
+			}
+			if raw = obj[key]; raw == nil {
+				return nil, nil
+			}
+		}
+	}
+	if string(raw) == "null" {
+		return nil, nil
+	}
+	return raw, nil
+}
+
+// jsonFieldString mengambil field path sebagai string. Angka dikembalikan dalam bentuk teksnya.
+func jsonFieldString(body []byte, path string) (string, error) {
+	raw, err := jsonField(body, path)
+	if err != nil || raw == nil {
+		return "", err
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s, nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(raw, &n); err != nil {
+		return "", fmt.Errorf("decode page field %q: expected string or number", path)
+	}
+	return n.String(), nil
+}
+
+// withQueryParams menambahkan atau mengganti parameter query pada rawURL.
+func withQueryParams(rawURL string, params map[string]string) (string, error) {
+	if len(params) == 0 {
+		return rawURL, nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid page url %q: %w", rawURL, err)
+	}
+	q := u.Query()
+	for k, v := range params {
+		q.Set(k, v)
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// parseLinkHeader mengurai header Link menjadi map rel ke URL, misalnya
+// `<https://api.example.com/items?page=2>; rel="next"`.
+func parseLinkHeader(value string) map[string]string {
+	links := make(map[string]string)
+	for value != "" {
+		start := strings.IndexByte(value, '<')
+		end := strings.IndexByte(value, '>')
+		if start < 0 || end < start {
+			break
+		}
+		target := value[start+1 : end]
+		value = value[end+1:]
+
+		params := value
+		if i := strings.IndexByte(value, '<'); i >= 0 {
+			params = value[:i]
+		}
+		value = value[len(params):]
+		for _, param := range strings.Split(params, ";") {
+			name, v, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || !strings.EqualFold(strings.TrimSpace(name), "rel") {
+				continue
+			}
+			for _, rel := range strings.Fields(strings.Trim(strings.TrimSpace(v), `",`)) {
+				if _, exists := links[strings.ToLower(rel)]; !exists {
+					links[strings.ToLower(rel)] = target
+				}
+			}
+		}
+	}
+	return links
+}
+
+// resolveReference me-resolve ref terhadap base. Jika salah satunya tidak valid, ref dikembalikan apa adanya.
+func resolveReference(base, ref string) string {
+	b, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	r, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return b.ResolveReference(r).String()
+}
@@ -0,0 +1,245 @@
+package gocommon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+type pageItem struct {
+	ID int `json:"id"`
+}
+
+// itemsJSON menghasilkan array JSON berisi item dengan ID from sampai to-1.
+func itemsJSON(from, to int) string {
+	s := "["
+	for i := from; i < to; i++ {
+		if i > from {
+			s += ","
+		}
+		s += `{"id":` + strconv.Itoa(i) + `}`
+	}
+	return s + "]"
+}
+
+func itemIDs(items []pageItem) []int {
+	ids := make([]int, len(items))
+	for i, it := range items {
+		ids[i] = it.ID
+	}
+	return ids
+}
+
+func TestPaginate_OffsetPages(t *testing.T) {
+	var requests int32
+	handler := func(ctx *fasthttp.RequestCtx) {
+		atomic.AddInt32(&requests, 1)
+		require.Equal(t, "id", string(ctx.QueryArgs().Peek("country")))
+		limit, _ := strconv.Atoi(string(ctx.QueryArgs().Peek("per_page")))
+		page, _ := strconv.Atoi(string(ctx.QueryArgs().Peek("page")))
+		from := (page - 1) * limit
+		to := min(from+limit, 7)
+		ctx.SetContentType("application/json")
+		fmt.Fprintf(ctx, `{"data":{"items":%s}}`, itemsJSON(from, max(from, to)))
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	pages := OffsetPages[pageItem](
+		PageRequest{Client: NewHTTPClient(WithBaseURL(addr)), URL: "/banks?country=id", ItemsField: "data.items"},
+		OffsetPaging{LimitParam: "per_page", Limit: 3},
+	)
+	items, err := PaginateAll(context.Background(), pages)
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 1, 2, 3, 4, 5, 6}, itemIDs(items))
+	// Halaman terakhir yang tidak penuh diikuti satu permintaan yang mengembalikan halaman kosong.
+	require.EqualValues(t, 4, requests)
+
+	// Halaman berikutnya hanya diambil jika dibutuhkan.
+	atomic.StoreInt32(&requests, 0)
+	for item, err := range Paginate(context.Background(), pages) {
+		require.NoError(t, err)
+		if item.ID == 1 {
+			break
+		}
+	}
+	require.EqualValues(t, 1, requests)
+}
+
+func TestPaginate_OffsetParam(t *testing.T) {
+	handler := func(ctx *fasthttp.RequestCtx) {
+		require.Empty(t, ctx.QueryArgs().Peek("page"))
+		offset, _ := strconv.Atoi(string(ctx.QueryArgs().Peek("offset")))
+		ctx.SetBodyString(itemsJSON(offset, min(offset+2, 5)))
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	items, err := PaginateAll(context.Background(), OffsetPages[pageItem](
+		PageRequest{URL: addr + "/items"}, OffsetPaging{OffsetParam: "offset", Limit: 2}))
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 1, 2, 3, 4}, itemIDs(items))
+}
+
+func TestPaginate_ZeroBasedPages(t *testing.T) {
+	var pages []string
+	handler := func(ctx *fasthttp.RequestCtx) {
+		pages = append(pages, string(ctx.QueryArgs().Peek("page")))
+		page, _ := strconv.Atoi(string(ctx.QueryArgs().Peek("page")))
+		ctx.SetBodyString(itemsJSON(page*2, min(page*2+2, 5)))
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	items, err := PaginateAll(context.Background(), OffsetPages[pageItem](
+		PageRequest{URL: addr + "/items"}, OffsetPaging{Limit: 2, ZeroBased: true}))
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 1, 2, 3, 4}, itemIDs(items))
+	require.Equal(t, []string{"0", "1", "2", "3"}, pages)
+}
+
+func TestPaginate_ServerCapsPageSize(t *testing.T) {
+	const total, maxPerPage = 120, 50
+	handler := func(ctx *fasthttp.RequestCtx) {
+		limit, _ := strconv.Atoi(string(ctx.QueryArgs().Peek("limit")))
+		limit = min(limit, maxPerPage)
+		from := 0
+		if ctx.QueryArgs().Has("offset") {
+			from, _ = strconv.Atoi(string(ctx.QueryArgs().Peek("offset")))
+		} else {
+			page, _ := strconv.Atoi(string(ctx.QueryArgs().Peek("page")))
+			from = (page - 1) * limit
+		}
+		to := min(from+limit, total)
+		fmt.Fprintf(ctx, `{"items":%s,"has_more":%t}`, itemsJSON(from, max(from, to)), to < total)
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	for name, cfg := range map[string]OffsetPaging{
+		"page":     {},
+		"offset":   {OffsetParam: "offset"},
+		"has more": {HasMoreField: "has_more"},
+	} {
+		items, err := PaginateAll(context.Background(), OffsetPages[pageItem](
+			PageRequest{URL: addr, ItemsField: "items"}, cfg))
+		require.NoError(t, err, name)
+		require.Len(t, items, total, name)
+		require.Equal(t, total-1, items[total-1].ID, name)
+	}
+}
+
+func TestPaginate_CursorPages(t *testing.T) {
+	handler := func(ctx *fasthttp.RequestCtx) {
+		require.Equal(t, "2", string(ctx.QueryArgs().Peek("size")))
+		switch string(ctx.QueryArgs().Peek("after")) {
+		case "":
+			ctx.SetBodyString(`{"items":[{"id":1},{"id":2}],"meta":{"next":"abc"}}`)
+		case "abc":
+			ctx.SetBodyString(`{"items":[{"id":3},{"id":4}],"meta":{"next":42}}`)
+		case "42":
+			ctx.SetBodyString(`{"items":[{"id":5}],"meta":{"next":null}}`)
+		default:
+			ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		}
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	items, err := PaginateAll(context.Background(), CursorPages[pageItem](
+		PageRequest{URL: addr + "/txns", ItemsField: "items"},
+		CursorPaging{CursorParam: "after", NextCursorField: "meta.next", LimitParam: "size", Limit: 2},
+	))
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2, 3, 4, 5}, itemIDs(items))
+}
+
+func TestPaginate_LinkPages(t *testing.T) {
+	var addr string
+	handler := func(ctx *fasthttp.RequestCtx) {
+		page, _ := strconv.Atoi(string(ctx.QueryArgs().Peek("page")))
+		switch page {
+		case 0:
+			ctx.Response.Header.Set("Link", `<`+addr+`/repos?page=2>; rel="next", <`+addr+`/repos?page=3>; rel="last"`)
+			ctx.SetBodyString(itemsJSON(0, 2))
+		case 2:
+			// URL relatif di-resolve terhadap URL halaman.
+			ctx.Response.Header.Set("Link", `</repos?page=3>; rel="next"; title="berikutnya", </repos>; rel="first"`)
+			ctx.SetBodyString(itemsJSON(2, 4))
+		default:
+			ctx.Response.Header.Set("Link", `</repos>; rel="first prev"`)
+			ctx.SetBodyString(itemsJSON(4, 5))
+		}
+	}
+	var closeServer func()
+	var err error
+	addr, closeServer, err = startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	items, err := PaginateAll(context.Background(), LinkPages[pageItem](PageRequest{URL: addr + "/repos"}))
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 1, 2, 3, 4}, itemIDs(items))
+}
+
+func TestPaginate_ErrorsAndCancellation(t *testing.T) {
+	handler := func(ctx *fasthttp.RequestCtx) {
+		if string(ctx.QueryArgs().Peek("page")) == "2" {
+			ctx.SetStatusCode(fasthttp.StatusBadGateway)
+			ctx.SetBodyString(`{"error":"upstream"}`)
+			return
+		}
+		ctx.SetBodyString(itemsJSON(0, 2))
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	pages := OffsetPages[pageItem](PageRequest{URL: addr}, OffsetPaging{Limit: 2})
+	items, err := PaginateAll(context.Background(), pages)
+	var herr *HTTPError
+	require.True(t, errors.As(err, &herr))
+	require.Equal(t, fasthttp.StatusBadGateway, herr.StatusCode)
+	require.Len(t, items, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var got []int
+	for item, err := range Paginate(ctx, pages) {
+		if err != nil {
+			require.ErrorIs(t, err, context.Canceled)
+			break
+		}
+		got = append(got, item.ID)
+		cancel()
+	}
+	require.Equal(t, []int{0, 1}, got)
+
+	// Server yang mengulang penanda halaman yang sama tidak menyebabkan loop tanpa akhir.
+	calls := 0
+	repeat := func(ctx context.Context, next string) (Page[int], error) {
+		calls++
+		return Page[int]{Items: []int{calls}, Next: "same"}, nil
+	}
+	all, err := PaginateAll(context.Background(), repeat)
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2}, all)
+}
+
+func TestParseLinkHeader(t *testing.T) {
+	links := parseLinkHeader(`<https://api.example.com/items?page=2&limit=10>; rel="next", <https://api.example.com/items?page=9>; rel=last`)
+	require.Equal(t, "https://api.example.com/items?page=2&limit=10", links["next"])
+	require.Equal(t, "https://api.example.com/items?page=9", links["last"])
+	require.Empty(t, parseLinkHeader("garbage"))
+}
@@ -0,0 +1,227 @@
+package gocommon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited dikembalikan (dibungkus RateLimitError) jika permintaan ditolak oleh rate limiter
+// dalam mode fail-fast. Gunakan errors.Is untuk memeriksanya.
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// RateLimitError adalah error bertipe yang dikembalikan saat rate limiter menolak permintaan.
+// Error ini cocok dengan ErrRateLimited melalui errors.Is.
+type RateLimitError struct {
+	// Host adalah host tujuan yang dibatasi.
+	Host string
+	// RetryAfter adalah perkiraan waktu tunggu sampai token berikutnya tersedia.
+	RetryAfter time.Duration
+}
+
+// Error mengimplementasikan interface error.
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s for host %s, retry after %s", ErrRateLimited.Error(), e.Host, e.RetryAfter)
+}
+
+// Is membuat errors.Is(err, ErrRateLimited) bernilai true.
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// RateLimit adalah batas token bucket: Rate permintaan per detik dengan lonjakan maksimum Burst.
+type RateLimit struct {
+	// Rate adalah jumlah permintaan per detik. Nilai <= 0 berarti tanpa batas.
+	Rate float64
+	// Burst adalah kapasitas bucket. Default: Rate dibulatkan ke atas, minimal 1.
+	Burst int
+}
+
+// RateLimiterConfig berisi pengaturan RateLimiter.
+type RateLimiterConfig struct {
+	// Limit adalah batas bawaan. Secara default setiap host mendapat bucket sendiri dengan batas ini.
+	Limit RateLimit
+	// Hosts berisi batas khusus per host (misalnya "api.partner.com"), menimpa Limit.
+	Hosts map[string]RateLimit
+	// Shared membuat Limit berlaku sebagai satu bucket untuk seluruh host yang tidak ada di Hosts,
+	// sehingga batasnya berlaku per klien, bukan per host.
+	Shared bool
+	// FailFast membuat permintaan langsung gagal dengan *RateLimitError alih-alih menunggu token.
+	// Dapat ditimpa per permintaan dengan WithRateLimitFailFast.
+	FailFast bool
+}
+
+// RateLimiter membatasi laju permintaan keluar dengan algoritma token bucket. Jika server membalas
+// 429 dengan header Retry-After, bucket host tersebut dijeda selama waktu itu. Satu RateLimiter
+// dapat dipakai bersama oleh beberapa HTTPClient.
+type RateLimiter struct {
+	cfg RateLimiterConfig
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+// tokenBucket menyimpan status satu bucket.
+type tokenBucket struct {
+	rate        float64
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+// sharedBucketKey adalah kunci bucket bersama pada mode Shared.
+const sharedBucketKey = "*"
+
+// NewRateLimiter membuat RateLimiter baru.
+//
+// Contoh penggunaan:
+//
+//	limiter := NewRateLimiter(RateLimiterConfig{
+//	    Limit: RateLimit{Rate: 10, Burst: 5},
+//	    Hosts: map[string]RateLimit{"api.partner.com": {Rate: 2}},
+//	})
+//	client := NewHTTPClient(WithRateLimiter(limiter))
+func NewRateLimiter(cfg RateLimiterConfig) *RateLimiter {
+	return &RateLimiter{
+		cfg:     cfg,
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// WithRateLimiter memasang rate limiter pada klien. Setiap percobaan, termasuk retry dan resume
+// download, memakai satu token.
+func WithRateLimiter(limiter *RateLimiter) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.limiter = limiter
+	}
+}
+
+// WithRateLimitFailFast mengatur apakah satu permintaan langsung gagal dengan *RateLimitError saat
+// token tidak tersedia, menimpa RateLimiterConfig.FailFast.
+func WithRateLimitFailFast(enabled bool) RequestOption {
+	return func(ro *requestOptions) {
+		ro.rateLimitFailFast = enabled
+	}
+}
+
+// Wait menunggu sampai token untuk host tersedia atau ctx selesai. Jika failFast bernilai true dan
+// token belum tersedia, Wait langsung mengembalikan *RateLimitError.
+func (l *RateLimiter) Wait(ctx context.Context, host string, failFast bool) error {
+	l.mu.Lock()
+	b := l.bucket(host)
+	if b == nil {
+		l.mu.Unlock()
+		return nil
+	}
+	for {
+		wait := b.delay(time.Now())
+		if wait > 0 && failFast {
+			l.mu.Unlock()
+			return &RateLimitError{Host: host, RetryAfter: wait}
+		}
+		// Token dipesan sekarang agar pemanggil berikutnya mengantre di belakangnya.
+		b.tokens--
+		pausedUntil := b.pausedUntil
+		l.mu.Unlock()
+
+		err := sleepContext(ctx, wait)
+		l.mu.Lock()
+		if err != nil {
+			b.tokens = math.Min(b.tokens+1, b.burst)
+			l.mu.Unlock()
+			return err
+		}
+		// Pause yang terjadi selama menunggu tidak terlihat saat token dipesan: kembalikan token
+		// lalu antre ulang sampai jeda berakhir.
+		if b.pausedUntil.After(pausedUntil) && time.Now().Before(b.pausedUntil) {
+			b.tokens = math.Min(b.tokens+1, b.burst)
+			continue
+		}
+		l.mu.Unlock()
+		return nil
+	}
+}
+
+// Pause menahan bucket host sampai d berlalu, misalnya setelah server membalas 429 dengan Retry-After.
+// Pemanggil Wait yang sedang menunggu ikut tertahan sampai jeda berakhir.
+func (l *RateLimiter) Pause(host string, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.bucket(host)
+	if b == nil {
+		return
+	}
+	now := time.Now()
+	b.refill(now)
+	if until := now.Add(d); until.After(b.pausedUntil) {
+		b.pausedUntil = until
+	}
+	if b.tokens > 0 {
+		b.tokens = 0
+	}
+}
+
+// bucket mengembalikan bucket untuk host, atau nil jika host tidak dibatasi. Pemanggil wajib memegang l.mu.
+func (l *RateLimiter) bucket(host string) *tokenBucket {
+	limit, ok := l.cfg.Hosts[host]
+	key := host
+	if !ok {
+		limit = l.cfg.Limit
+		if l.cfg.Shared {
+			key = sharedBucketKey
+		}
+	}
+	if limit.Rate <= 0 {
+		return nil
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		burst := limit.Burst
+		if burst <= 0 {
+			burst = int(math.Max(1, math.Ceil(limit.Rate)))
+		}
+		b = &tokenBucket{
+			rate:   limit.Rate,
+			burst:  float64(burst),
+			tokens: float64(burst),
+			last:   time.Now(),
+		}
+		l.buckets[key] = b
+	}
+	return b
+}
+
+// refill menambah token sesuai waktu yang berlalu sejak pengisian terakhir.
+func (b *tokenBucket) refill(now time.Time) {
+	if now.After(b.last) {
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+	}
+}
+
+// delay mengembalikan waktu tunggu sampai satu token tersedia.
+func (b *tokenBucket) delay(now time.Time) time.Duration {
+	if now.Before(b.pausedUntil) {
+		// Selama dijeda token tidak bertambah; pengisian dilanjutkan dari akhir jeda.
+		b.last = b.pausedUntil
+		wait := b.pausedUntil.Sub(now)
+		if b.tokens < 1 {
+			wait += time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		}
+		return wait
+	}
+	b.refill(now)
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
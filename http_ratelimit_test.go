@@ -0,0 +1,120 @@
+package gocommon
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestRateLimiter_BlocksUntilTokenAvailable(t *testing.T) {
+	limiter := NewRateLimiter(RateLimiterConfig{Limit: RateLimit{Rate: 20, Burst: 2}})
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		require.NoError(t, limiter.Wait(context.Background(), "partner", false))
+	}
+	// Two tokens are available immediately; the next two take 50ms each.
+	require.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, limiter.Wait(ctx, "partner", false), context.DeadlineExceeded)
+
+	// Other hosts have their own bucket.
+	require.NoError(t, limiter.Wait(context.Background(), "other", true))
+}
+
+func TestRateLimiter_PauseHoldsQueuedWaiters(t *testing.T) {
+	limiter := NewRateLimiter(RateLimiterConfig{Limit: RateLimit{Rate: 10, Burst: 1}})
+	require.NoError(t, limiter.Wait(context.Background(), "partner", false))
+
+	// Tiga pemanggil mengantre (jadwal awal 100ms, 200ms, dan 300ms) sebelum server membalas 429.
+	var wg sync.WaitGroup
+	woke := make(chan time.Time, 3)
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.NoError(t, limiter.Wait(context.Background(), "partner", false))
+			woke <- time.Now()
+		}()
+	}
+	time.Sleep(30 * time.Millisecond)
+	paused := time.Now()
+	limiter.Pause("partner", 500*time.Millisecond)
+
+	wg.Wait()
+	close(woke)
+	var times []time.Duration
+	for at := range woke {
+		times = append(times, at.Sub(paused))
+	}
+	require.Len(t, times, 3)
+	for _, d := range times {
+		require.GreaterOrEqual(t, d, 490*time.Millisecond)
+	}
+}
+
+func TestRateLimiter_FailFastAndShared(t *testing.T) {
+	limiter := NewRateLimiter(RateLimiterConfig{
+		Limit:  RateLimit{Rate: 1},
+		Hosts:  map[string]RateLimit{"unlimited": {}},
+		Shared: true,
+	})
+
+	require.NoError(t, limiter.Wait(context.Background(), "a", true))
+	err := limiter.Wait(context.Background(), "b", true)
+	require.ErrorIs(t, err, ErrRateLimited)
+	var rlErr *RateLimitError
+	require.True(t, errors.As(err, &rlErr))
+	require.Equal(t, "b", rlErr.Host)
+	require.Greater(t, rlErr.RetryAfter, 900*time.Millisecond)
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, limiter.Wait(context.Background(), "unlimited", true))
+	}
+}
+
+func TestRateLimiter_ClientAndRetryAfter(t *testing.T) {
+	var calls int32
+	handler := func(ctx *fasthttp.RequestCtx) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			ctx.Response.Header.Set("Retry-After", "1")
+			ctx.SetStatusCode(fasthttp.StatusTooManyRequests)
+			return
+		}
+		ctx.SetStatusCode(fasthttp.StatusOK)
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	limiter := NewRateLimiter(RateLimiterConfig{Limit: RateLimit{Rate: 100}, FailFast: true})
+	client := NewHTTPClient(WithRateLimiter(limiter))
+
+	_, status, err := client.GetJSON(addr, nil)
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusTooManyRequests, status)
+
+	// The bucket is paused for the Retry-After duration.
+	_, _, err = client.GetJSON(addr, nil)
+	require.ErrorIs(t, err, ErrRateLimited)
+	var rlErr *RateLimitError
+	require.True(t, errors.As(err, &rlErr))
+	require.Equal(t, strings.TrimPrefix(addr, "http://"), rlErr.Host)
+	require.EqualValues(t, 1, atomic.LoadInt32(&calls))
+
+	// Waiting mode blocks until the pause is over.
+	start := time.Now()
+	_, status, err = client.GetJSON(addr, nil, WithRateLimitFailFast(false))
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusOK, status)
+	require.Greater(t, time.Since(start), 500*time.Millisecond)
+}
@@ -0,0 +1,127 @@
+package gocommon
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// redactedValue adalah nilai pengganti untuk data rahasia pada log dan cassette.
+const redactedValue = "[REDACTED]"
+
+// defaultRedactHeaders adalah header rahasia yang disamarkan secara default.
+var defaultRedactHeaders = []string{
+	"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Signature", "X-Api-Key",
+}
+
+// defaultRedactFields adalah field body JSON/form dan parameter query rahasia yang disamarkan secara
+// default oleh SlogMiddleware dan Cassette.
+var defaultRedactFields = []string{
+	"password", "pin", "otp", "card_number", "cvv", "access_token", "refresh_token", "client_secret",
+}
+
+// redactHeaders menyamarkan nilai header dengan nama di names pada h.
+func redactHeaders(h http.Header, names []string) {
+	for _, name := range names {
+		if values := h.Values(name); len(values) > 0 {
+			redacted := make([]string, len(values))
+			for i := range redacted {
+				redacted[i] = redactedValue
+			}
+			h[http.CanonicalHeaderKey(name)] = redacted
+		}
+	}
+}
+
+// redactURL menyamarkan parameter query yang namanya ada di fields.
+func redactURL(raw string, fields []string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.RawQuery == "" {
+		return raw
+	}
+	q := u.Query()
+	if !redactValues(q, fields) {
+		return raw
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// redactBody menyamarkan field di fields pada body JSON atau form. Body lain dikembalikan apa adanya.
+func redactBody(body, contentType string, fields []string) string {
+	if body == "" || len(fields) == 0 {
+		return body
+	}
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		values, err := url.ParseQuery(body)
+		if err == nil && redactValues(values, fields) {
+			return values.Encode()
+		}
+		return body
+	}
+
+	var v interface{}
+	dec := json.NewDecoder(strings.NewReader(body))
+	dec.UseNumber()
+	if dec.Decode(&v) != nil {
+		return body
+	}
+	if !redactJSON(v, fields) {
+		return body
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return string(out)
+}
+
+// redactValues menyamarkan key rahasia pada values dan melaporkan apakah ada yang diubah.
+func redactValues(values url.Values, fields []string) bool {
+	changed := false
+	for key, vs := range values {
+		if isRedactedField(key, fields) {
+			for i := range vs {
+				vs[i] = redactedValue
+			}
+			changed = true
+		}
+	}
+	return changed
+}
+
+// redactJSON menyamarkan field rahasia secara rekursif dan melaporkan apakah ada yang diubah.
+func redactJSON(v interface{}, fields []string) bool {
+	changed := false
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, child := range t {
+			if isRedactedField(k, fields) {
+				t[k] = redactedValue
+				changed = true
+				continue
+			}
+			if redactJSON(child, fields) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, child := range t {
+			if redactJSON(child, fields) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// isRedactedField melaporkan apakah name termasuk fields (tanpa membedakan huruf besar/kecil).
+func isRedactedField(name string, fields []string) bool {
+	for _, f := range fields {
+		if strings.EqualFold(f, name) {
+			return true
+		}
+	}
+	return false
+}
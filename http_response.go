@@ -0,0 +1,342 @@
+package gocommon
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-playground/validator"
+	"github.com/valyala/fasthttp"
+)
+
+// jsonContentType adalah Content-Type respons JSON yang ditulis Responder.
+const jsonContentType = "application/json; charset=utf-8"
+
+// OmitField dipakai sebagai nama field pada ResponseEnvelope agar field tersebut tidak ditulis.
+const OmitField = "-"
+
+// ResponseEnvelope menentukan nama field envelope respons JSON. Field yang dibiarkan kosong memakai
+// nama dari DefaultResponseEnvelope; isi dengan OmitField agar field tidak ditulis.
+type ResponseEnvelope struct {
+	// SuccessField berisi true untuk status < 400. Default "success".
+	SuccessField string
+	// MessageField berisi pesan untuk pengguna. Default "message".
+	MessageField string
+	// DataField berisi payload respons sukses. Default "data".
+	DataField string
+	// ErrorsField berisi detail error per field pada respons validasi. Default "errors".
+	ErrorsField string
+	// MetaField berisi metadata paginasi. Default "meta".
+	MetaField string
+	// CodeField berisi kode error aplikasi dari ErrorWithCode, misalnya "INSUFFICIENT_BALANCE".
+	// Default "code".
+	CodeField string
+	// StatusField, jika diisi, berisi kode status HTTP. Default tidak ditulis.
+	StatusField string
+}
+
+// withDefaults mengisi field kosong dengan nama dari DefaultResponseEnvelope dan mengganti OmitField
+// dengan string kosong, yang dilewati saat envelope ditulis.
+func (env ResponseEnvelope) withDefaults() ResponseEnvelope {
+	fields := []struct {
+		name *string
+		def  string
+	}{
+		{&env.SuccessField, DefaultResponseEnvelope.SuccessField},
+		{&env.MessageField, DefaultResponseEnvelope.MessageField},
+		{&env.DataField, DefaultResponseEnvelope.DataField},
+		{&env.ErrorsField, DefaultResponseEnvelope.ErrorsField},
+		{&env.MetaField, DefaultResponseEnvelope.MetaField},
+		{&env.CodeField, DefaultResponseEnvelope.CodeField},
+		{&env.StatusField, DefaultResponseEnvelope.StatusField},
+	}
+	for _, f := range fields {
+		switch *f.name {
+		case "":
+			*f.name = f.def
+		case OmitField:
+			*f.name = ""
+		}
+	}
+	return env
+}
+
+// DefaultResponseEnvelope adalah envelope bawaan:
+//
+//	{"success": true, "message": "OK", "data": {...}}
+var DefaultResponseEnvelope = ResponseEnvelope{
+	SuccessField: "success",
+	MessageField: "message",
+	DataField:    "data",
+	ErrorsField:  "errors",
+	MetaField:    "meta",
+	CodeField:    "code",
+}
+
+// PaginationMeta adalah metadata paginasi pada respons Paginated.
+type PaginationMeta struct {
+	Page       int   `json:"page"`
+	PerPage    int   `json:"per_page"`
+	Total      int64 `json:"total"`
+	TotalPages int   `json:"total_pages"`
+	// NextCursor diisi untuk paginasi berbasis cursor.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// FieldErrors adalah pesan error validasi per field. FieldErrors memenuhi interface error sehingga
+// dapat dikembalikan dari fungsi validasi lalu diteruskan ke ValidationError.
+type FieldErrors map[string]string
+
+// Error mengimplementasikan interface error.
+func (fe FieldErrors) Error() string {
+	fields := make([]string, 0, len(fe))
+	for field, msg := range fe {
+		fields = append(fields, field+": "+msg)
+	}
+	sort.Strings(fields)
+	return "validation failed: " + strings.Join(fields, "; ")
+}
+
+// Responder menulis respons JSON dengan envelope yang seragam ke *fasthttp.RequestCtx.
+//
+// Contoh penggunaan:
+//
+//	responder := NewResponder(ResponseEnvelope{
+//	    SuccessField: "status", MessageField: "msg", DataField: "result", MetaField: OmitField,
+//	})
+//	responder.Success(ctx, "OK", user)
+//	// {"status":true,"msg":"OK","result":{...}}
+type Responder struct {
+	envelope ResponseEnvelope
+}
+
+// NewResponder membuat Responder dengan envelope env. Field env yang kosong memakai nama bawaan.
+func NewResponder(env ResponseEnvelope) *Responder {
+	return &Responder{envelope: env.withDefaults()}
+}
+
+// DefaultResponder dipakai oleh fungsi paket seperti WriteSuccess dan WriteError.
+// Ganti dengan SetResponseEnvelope saat inisialisasi aplikasi.
+var DefaultResponder = NewResponder(DefaultResponseEnvelope)
+
+// SetResponseEnvelope mengganti envelope DefaultResponder. Panggil sekali saat inisialisasi,
+// sebelum server mulai melayani permintaan.
+func SetResponseEnvelope(env ResponseEnvelope) {
+	DefaultResponder = NewResponder(env)
+}
+
+// envelopeField adalah pasangan nama dan nilai field envelope, ditulis sesuai urutan.
+type envelopeField struct {
+	name  string
+	value interface{}
+}
+
+// write menyusun envelope lalu menulisnya ke ctx dengan kode status status.
+func (r *Responder) write(ctx *fasthttp.RequestCtx, status int, fields ...envelopeField) {
+	env := r.envelope
+	all := make([]envelopeField, 0, len(fields)+2)
+	all = append(all, envelopeField{env.SuccessField, status < 400})
+	if env.StatusField != "" {
+		all = append(all, envelopeField{env.StatusField, status})
+	}
+	all = append(all, fields...)
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	n := 0
+	for _, f := range all {
+		if f.name == "" {
+			continue
+		}
+		key, _ := json.Marshal(f.name)
+		value, err := json.Marshal(f.value)
+		if err != nil {
+			r.writeMarshalError(ctx)
+			return
+		}
+		if n > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+		n++
+	}
+	buf.WriteByte('}')
+
+	ctx.SetStatusCode(status)
+	ctx.SetContentType(jsonContentType)
+	ctx.SetBody(buf.Bytes())
+}
+
+// writeMarshalError menulis respons 500 jika payload tidak dapat di-marshal.
+func (r *Responder) writeMarshalError(ctx *fasthttp.RequestCtx) {
+	r.write(ctx, fasthttp.StatusInternalServerError,
+		envelopeField{r.envelope.MessageField, "internal server error"})
+}
+
+// JSON menulis v apa adanya sebagai JSON tanpa envelope.
+func (r *Responder) JSON(ctx *fasthttp.RequestCtx, status int, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		r.writeMarshalError(ctx)
+		return
+	}
+	ctx.SetStatusCode(status)
+	ctx.SetContentType(jsonContentType)
+	ctx.SetBody(body)
+}
+
+// Success menulis respons 200 OK berisi message dan data.
+func (r *Responder) Success(ctx *fasthttp.RequestCtx, message string, data interface{}) {
+	r.write(ctx, fasthttp.StatusOK,
+		envelopeField{r.envelope.MessageField, message},
+		envelopeField{r.envelope.DataField, data})
+}
+
+// Created menulis respons 201 Created berisi message dan data.
+func (r *Responder) Created(ctx *fasthttp.RequestCtx, message string, data interface{}) {
+	r.write(ctx, fasthttp.StatusCreated,
+		envelopeField{r.envelope.MessageField, message},
+		envelopeField{r.envelope.DataField, data})
+}
+
+// Paginated menulis respons 200 OK berisi data dan metadata paginasi. Jika meta.TotalPages nol,
+// nilainya dihitung dari Total dan PerPage.
+func (r *Responder) Paginated(ctx *fasthttp.RequestCtx, message string, data interface{}, meta PaginationMeta) {
+	if meta.TotalPages == 0 && meta.PerPage > 0 {
+		meta.TotalPages = int((meta.Total + int64(meta.PerPage) - 1) / int64(meta.PerPage))
+	}
+	r.write(ctx, fasthttp.StatusOK,
+		envelopeField{r.envelope.MessageField, message},
+		envelopeField{r.envelope.DataField, data},
+		envelopeField{r.envelope.MetaField, meta})
+}
+
+// Error menulis respons error dengan kode status status (4xx atau 5xx). Status di bawah 400
+// diganti menjadi 500.
+func (r *Responder) Error(ctx *fasthttp.RequestCtx, status int, message string) {
+	r.ErrorWithCode(ctx, status, "", message)
+}
+
+// ErrorWithCode sama dengan Error, tetapi menyertakan kode error aplikasi pada CodeField.
+func (r *Responder) ErrorWithCode(ctx *fasthttp.RequestCtx, status int, code, message string) {
+	if status < 400 {
+		status = fasthttp.StatusInternalServerError
+	}
+	fields := []envelopeField{{r.envelope.MessageField, message}}
+	if code != "" {
+		fields = append(fields, envelopeField{r.envelope.CodeField, code})
+	}
+	r.write(ctx, status, fields...)
+}
+
+// ValidationError menulis respons 422 Unprocessable Entity berisi pesan error per field untuk err
+// bertipe FieldErrors atau validator.ValidationErrors (hasil Validator.Struct). Error lain, misalnya
+// body JSON yang tidak valid, ditulis sebagai 400 Bad Request dengan pesan err. Jika err nil,
+// respons 500 ditulis karena pemanggil seharusnya tidak memanggil ValidationError tanpa error.
+//
+// Nama field mengikuti validator.FieldError.Field; daftarkan RegisterTagNameFunc pada Validator
+// agar nama field memakai tag json.
+//
+// Contoh penggunaan:
+//
+//	if err := Validator.Struct(req); err != nil {
+//	    WriteValidationError(ctx, err)
+//	    return
+//	}
+func (r *Responder) ValidationError(ctx *fasthttp.RequestCtx, err error) {
+	if err == nil {
+		r.Error(ctx, fasthttp.StatusInternalServerError, "internal server error")
+		return
+	}
+	var fieldErrs FieldErrors
+	var validationErrs validator.ValidationErrors
+	switch {
+	case errors.As(err, &fieldErrs):
+	case errors.As(err, &validationErrs):
+		fieldErrs = make(FieldErrors, len(validationErrs))
+		for _, fe := range validationErrs {
+			fieldErrs[fe.Field()] = validationMessage(fe)
+		}
+	default:
+		r.Error(ctx, fasthttp.StatusBadRequest, err.Error())
+		return
+	}
+	r.write(ctx, fasthttp.StatusUnprocessableEntity,
+		envelopeField{r.envelope.MessageField, "validation failed"},
+		envelopeField{r.envelope.ErrorsField, fieldErrs})
+}
+
+// validationMessage menerjemahkan tag validator menjadi pesan yang mudah dibaca.
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "min":
+		return "must be at least " + fe.Param()
+	case "max":
+		return "must be at most " + fe.Param()
+	case "len":
+		return "must be exactly " + fe.Param()
+	case "oneof":
+		return "must be one of " + fe.Param()
+	default:
+		if fe.Param() != "" {
+			return fmt.Sprintf("failed on %s=%s", fe.Tag(), fe.Param())
+		}
+		return "failed on " + fe.Tag()
+	}
+}
+
+// WriteJSON menulis v sebagai JSON tanpa envelope melalui DefaultResponder.
+func WriteJSON(ctx *fasthttp.RequestCtx, status int, v interface{}) {
+	DefaultResponder.JSON(ctx, status, v)
+}
+
+// WriteSuccess menulis respons 200 OK melalui DefaultResponder.
+//
+// Contoh penggunaan:
+//
+//	func getBanks(ctx *fasthttp.RequestCtx) {
+//	    WriteSuccess(ctx, "OK", banks)
+//	    // {"success":true,"message":"OK","data":[...]}
+//	}
+func WriteSuccess(ctx *fasthttp.RequestCtx, message string, data interface{}) {
+	DefaultResponder.Success(ctx, message, data)
+}
+
+// WriteCreated menulis respons 201 Created melalui DefaultResponder.
+func WriteCreated(ctx *fasthttp.RequestCtx, message string, data interface{}) {
+	DefaultResponder.Created(ctx, message, data)
+}
+
+// WritePaginated menulis respons daftar berpaginasi melalui DefaultResponder.
+func WritePaginated(ctx *fasthttp.RequestCtx, message string, data interface{}, meta PaginationMeta) {
+	DefaultResponder.Paginated(ctx, message, data, meta)
+}
+
+// WriteError menulis respons error melalui DefaultResponder.
+//
+// Contoh penggunaan:
+//
+//	WriteError(ctx, fasthttp.StatusNotFound, "user not found")
+//	// {"success":false,"message":"user not found"}
+func WriteError(ctx *fasthttp.RequestCtx, status int, message string) {
+	DefaultResponder.Error(ctx, status, message)
+}
+
+// WriteErrorWithCode menulis respons error beserta kode error aplikasi melalui DefaultResponder.
+func WriteErrorWithCode(ctx *fasthttp.RequestCtx, status int, code, message string) {
+	DefaultResponder.ErrorWithCode(ctx, status, code, message)
+}
+
+// WriteValidationError menulis respons error validasi melalui DefaultResponder.
+func WriteValidationError(ctx *fasthttp.RequestCtx, err error) {
+	DefaultResponder.ValidationError(ctx, err)
+}
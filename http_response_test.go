@@ -0,0 +1,131 @@
+package gocommon
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-playground/validator"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestResponder_SuccessAndCreated(t *testing.T) {
+	var ctx fasthttp.RequestCtx
+	WriteSuccess(&ctx, "OK", map[string]string{"code": "014"})
+	require.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+	require.Equal(t, jsonContentType, string(ctx.Response.Header.ContentType()))
+	require.Equal(t, `{"success":true,"message":"OK","data":{"code":"014"}}`, string(ctx.Response.Body()))
+
+	ctx.Response.Reset()
+	WriteCreated(&ctx, "user created", nil)
+	require.Equal(t, fasthttp.StatusCreated, ctx.Response.StatusCode())
+	require.Equal(t, `{"success":true,"message":"user created","data":null}`, string(ctx.Response.Body()))
+
+	ctx.Response.Reset()
+	WriteJSON(&ctx, fasthttp.StatusAccepted, []int{1, 2})
+	require.Equal(t, fasthttp.StatusAccepted, ctx.Response.StatusCode())
+	require.Equal(t, `[1,2]`, string(ctx.Response.Body()))
+
+	ctx.Response.Reset()
+	WriteSuccess(&ctx, "OK", make(chan int))
+	require.Equal(t, fasthttp.StatusInternalServerError, ctx.Response.StatusCode())
+	require.Contains(t, string(ctx.Response.Body()), `"success":false`)
+}
+
+func TestResponder_ErrorsAndPagination(t *testing.T) {
+	var ctx fasthttp.RequestCtx
+	WriteError(&ctx, fasthttp.StatusNotFound, "user not found")
+	require.Equal(t, fasthttp.StatusNotFound, ctx.Response.StatusCode())
+	require.Equal(t, `{"success":false,"message":"user not found"}`, string(ctx.Response.Body()))
+
+	ctx.Response.Reset()
+	WriteErrorWithCode(&ctx, fasthttp.StatusConflict, "DUPLICATE_TRX", "transaction already exists")
+	require.Equal(t, fasthttp.StatusConflict, ctx.Response.StatusCode())
+	require.Equal(t, `{"success":false,"message":"transaction already exists","code":"DUPLICATE_TRX"}`, string(ctx.Response.Body()))
+
+	ctx.Response.Reset()
+	WriteError(&ctx, fasthttp.StatusOK, "not an error status")
+	require.Equal(t, fasthttp.StatusInternalServerError, ctx.Response.StatusCode())
+
+	ctx.Response.Reset()
+	WritePaginated(&ctx, "OK", []int{1, 2}, PaginationMeta{Page: 1, PerPage: 2, Total: 5})
+	require.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+	require.JSONEq(t, `{"success":true,"message":"OK","data":[1,2],
+		"meta":{"page":1,"per_page":2,"total":5,"total_pages":3}}`, string(ctx.Response.Body()))
+}
+
+func TestResponder_ValidationError(t *testing.T) {
+	type registerRequest struct {
+		Username string `validate:"required,min=3"`
+		Email    string `validate:"required,email"`
+		Gender   string `validate:"oneof=M F"`
+	}
+	err := validator.New().Struct(registerRequest{Username: "ab", Email: "bukan-email", Gender: "M"})
+	require.Error(t, err)
+
+	var ctx fasthttp.RequestCtx
+	WriteValidationError(&ctx, err)
+	require.Equal(t, fasthttp.StatusUnprocessableEntity, ctx.Response.StatusCode())
+	require.JSONEq(t, `{"success":false,"message":"validation failed","errors":{
+		"Username":"must be at least 3","Email":"must be a valid email address"}}`, string(ctx.Response.Body()))
+
+	ctx.Response.Reset()
+	WriteValidationError(&ctx, FieldErrors{"amount": "must be positive"})
+	require.Equal(t, fasthttp.StatusUnprocessableEntity, ctx.Response.StatusCode())
+	require.JSONEq(t, `{"success":false,"message":"validation failed","errors":{"amount":"must be positive"}}`,
+		string(ctx.Response.Body()))
+
+	ctx.Response.Reset()
+	WriteValidationError(&ctx, errors.New("invalid json body"))
+	require.Equal(t, fasthttp.StatusBadRequest, ctx.Response.StatusCode())
+	require.Equal(t, `{"success":false,"message":"invalid json body"}`, string(ctx.Response.Body()))
+
+	ctx.Response.Reset()
+	WriteValidationError(&ctx, nil)
+	require.Equal(t, fasthttp.StatusInternalServerError, ctx.Response.StatusCode())
+	require.Equal(t, `{"success":false,"message":"internal server error"}`, string(ctx.Response.Body()))
+}
+
+func TestResponder_CustomEnvelope(t *testing.T) {
+	prev := DefaultResponder
+	defer func() { DefaultResponder = prev }()
+
+	SetResponseEnvelope(ResponseEnvelope{
+		SuccessField: OmitField,
+		StatusField:  "status",
+		MessageField: "msg",
+		DataField:    "result",
+		MetaField:    OmitField,
+		CodeField:    "error_code",
+	})
+
+	var ctx fasthttp.RequestCtx
+	WriteSuccess(&ctx, "OK", 1)
+	require.Equal(t, `{"status":200,"msg":"OK","result":1}`, string(ctx.Response.Body()))
+
+	ctx.Response.Reset()
+	WriteErrorWithCode(&ctx, fasthttp.StatusBadGateway, "UPSTREAM", "bank timeout")
+	require.Equal(t, `{"status":502,"msg":"bank timeout","error_code":"UPSTREAM"}`, string(ctx.Response.Body()))
+
+	// Field OmitField (di sini MetaField) tidak ditulis.
+	ctx.Response.Reset()
+	WritePaginated(&ctx, "OK", []int{}, PaginationMeta{Page: 1, PerPage: 10})
+	require.Equal(t, `{"status":200,"msg":"OK","result":[]}`, string(ctx.Response.Body()))
+}
+
+func TestResponder_EnvelopeDefaults(t *testing.T) {
+	responder := NewResponder(ResponseEnvelope{SuccessField: "status", MessageField: "msg", DataField: "result"})
+
+	var ctx fasthttp.RequestCtx
+	responder.ErrorWithCode(&ctx, fasthttp.StatusConflict, "DUPLICATE_TRX", "transaction already exists")
+	require.Equal(t, `{"status":false,"msg":"transaction already exists","code":"DUPLICATE_TRX"}`, string(ctx.Response.Body()))
+
+	ctx.Response.Reset()
+	responder.ValidationError(&ctx, FieldErrors{"amount": "must be positive"})
+	require.Equal(t, `{"status":false,"msg":"validation failed","errors":{"amount":"must be positive"}}`, string(ctx.Response.Body()))
+
+	ctx.Response.Reset()
+	responder.Paginated(&ctx, "OK", []int{}, PaginationMeta{Page: 1, PerPage: 10})
+	require.Equal(t, `{"status":true,"msg":"OK","result":[],"meta":{"page":1,"per_page":10,"total":0,"total_pages":0}}`,
+		string(ctx.Response.Body()))
+}
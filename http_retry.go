@@ -0,0 +1,251 @@
+package gocommon
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// IdempotencyKeyHeader adalah nama header yang dipakai untuk mengirim idempotency key.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+const (
+	defaultRetryBaseDelay = 100 * time.Millisecond
+	defaultRetryMaxDelay  = 5 * time.Second
+)
+
+// defaultRetryableStatus adalah kode status yang di-retry jika RetryPolicy.RetryableStatus kosong.
+var defaultRetryableStatus = []int{
+	fasthttp.StatusRequestTimeout,
+	fasthttp.StatusTooManyRequests,
+	fasthttp.StatusInternalServerError,
+	fasthttp.StatusBadGateway,
+	fasthttp.StatusServiceUnavailable,
+	fasthttp.StatusGatewayTimeout,
+}
+
+// RetryPolicy mengatur kapan dan berapa lama sebuah permintaan HTTP diulang.
+//
+// Jeda antar percobaan memakai exponential backoff dengan full jitter, yaitu nilai acak antara 0 dan
+// min(MaxDelay, BaseDelay * 2^(percobaan-1)). Jika respons membawa header Retry-After, nilai tersebut
+// dipakai sebagai jeda; bila melebihi MaxDelay, permintaan tidak di-retry lagi.
+//
+// Hanya metode idempoten (GET, HEAD, OPTIONS, TRACE, PUT, DELETE) yang di-retry, kecuali permintaan
+// membawa idempotency key (lihat WithIdempotencyKey).
+type RetryPolicy struct {
+	// MaxAttempts adalah jumlah total percobaan termasuk percobaan pertama. Nilai <= 1 berarti tanpa retry.
+	MaxAttempts int
+	// BaseDelay adalah jeda dasar backoff. Default 100 ms.
+	BaseDelay time.Duration
+	// MaxDelay adalah batas atas jeda antar percobaan. Default 5 detik.
+	MaxDelay time.Duration
+	// RetryableStatus adalah daftar kode status yang di-retry. Default 408, 429, 500, 502, 503, dan 504.
+	RetryableStatus []int
+	// OnAttempt dipanggil setelah setiap percobaan, berguna untuk logging.
+	OnAttempt func(attempt RetryAttempt)
+}
+
+// RetryAttempt berisi informasi satu percobaan yang dilaporkan ke RetryPolicy.OnAttempt.
+type RetryAttempt struct {
+	// Context adalah context permintaan, berguna untuk membaca nilai seperti RequestIDFromContext.
+	Context context.Context
+	// Attempt adalah nomor percobaan, dimulai dari 1.
+	Attempt    int
+	Method     string
+	URL        string
+	StatusCode int
+	Err        error
+	// WillRetry bernilai true jika permintaan akan diulang setelah Delay.
+	WillRetry bool
+	Delay     time.Duration
+}
+
+// WithRetry mengatur kebijakan retry untuk satu permintaan, menimpa kebijakan bawaan klien.
+//
+// Contoh penggunaan:
+//
+//	resp, status, err := HTTPGetJSON(url, nil, WithRetry(RetryPolicy{
+//	    MaxAttempts: 3,
+//	    OnAttempt: func(a RetryAttempt) {
+//	        log.Printf("percobaan %d: status=%d err=%v", a.Attempt, a.StatusCode, a.Err)
+//	    },
+//	}))
+func WithRetry(policy RetryPolicy) RequestOption {
+	return func(ro *requestOptions) {
+		ro.retry = &policy
+	}
+}
+
+// WithIdempotencyKey menambahkan header Idempotency-Key pada permintaan. Dengan key ini, metode yang
+// tidak idempoten seperti POST dan PATCH ikut di-retry sesuai RetryPolicy.
+func WithIdempotencyKey(key string) RequestOption {
+	return func(ro *requestOptions) {
+		ro.idempotencyKey = key
+	}
+}
+
+// maxAttempts mengembalikan jumlah percobaan yang diizinkan untuk permintaan req.
+// Body berupa stream tidak dapat dikirim ulang sehingga tidak pernah di-retry.
+func (p *RetryPolicy) maxAttempts(req *fasthttp.Request) int {
+	if p == nil || p.MaxAttempts <= 1 || req.IsBodyStream() {
+		return 1
+	}
+	if !isIdempotentMethod(string(req.Header.Method())) && len(req.Header.Peek(IdempotencyKeyHeader)) == 0 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// isRetryableStatus melaporkan apakah kode status termasuk yang boleh di-retry.
+func (p *RetryPolicy) isRetryableStatus(status int) bool {
+	list := p.RetryableStatus
+	if len(list) == 0 {
+		list = defaultRetryableStatus
+	}
+	for _, s := range list {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff menghitung jeda sebelum percobaan berikutnya. Nilai ok bernilai false jika
+// Retry-After dari server melebihi MaxDelay sehingga retry sebaiknya dihentikan.
+func (p *RetryPolicy) backoff(attempt int, resp *fasthttp.Response) (delay time.Duration, ok bool) {
+	maxDelay := p.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = defaultRetryMaxDelay
+	}
+
+	if resp != nil {
+		if d, found := parseRetryAfter(resp.Header.Peek("Retry-After")); found {
+			if d > maxDelay {
+				return 0, false
+			}
+			return d, true
+		}
+	}
+
+	base := p.BaseDelay
+	if base <= 0 {
+		base = defaultRetryBaseDelay
+	}
+	ceiling := maxDelay
+	if shift := attempt - 1; shift < 32 {
+		if d := base << shift; d > 0 && d < ceiling {
+			ceiling = d
+		}
+	}
+	return rand.N(ceiling + 1), true
+}
+
+// parseRetryAfter membaca header Retry-After dalam format detik maupun HTTP-date.
+func parseRetryAfter(v []byte) (time.Duration, bool) {
+	if len(v) == 0 {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(string(v)); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(string(v)); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// isIdempotentMethod melaporkan apakah metode HTTP bersifat idempoten menurut RFC 9110.
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case fasthttp.MethodGet, fasthttp.MethodHead, fasthttp.MethodOptions,
+		fasthttp.MethodTrace, fasthttp.MethodPut, fasthttp.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// doWithRetry mengirim req dan mengulanginya sesuai kebijakan retry pada ro.
+// Setelah fungsi kembali, resp berisi respons dari percobaan terakhir.
+func (c *HTTPClient) doWithRetry(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response, ro *requestOptions) error {
+	policy := ro.retry
+	attempts := policy.maxAttempts(req)
+
+	host := string(req.URI().Host())
+	send := c.handler(ro)
+
+	for attempt := 1; ; attempt++ {
+		resp.Reset()
+		if c.limiter != nil {
+			if err := c.limiter.Wait(ctx, host, ro.rateLimitFailFast); err != nil {
+				return err
+			}
+		}
+		if c.breaker != nil {
+			if err := c.breaker.allow(host); err != nil {
+				return err
+			}
+		}
+		err := send(ctx, req, resp)
+
+		status := 0
+		if err == nil {
+			status = resp.StatusCode()
+		}
+		if c.breaker != nil {
+			if ctx.Err() != nil {
+				c.breaker.release(host)
+			} else {
+				c.breaker.record(host, status, err)
+			}
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if c.limiter != nil && status == fasthttp.StatusTooManyRequests {
+			if d, found := parseRetryAfter(resp.Header.Peek("Retry-After")); found {
+				c.limiter.Pause(host, d)
+			}
+		}
+
+		retry := attempt < attempts && (err != nil || policy.isRetryableStatus(status))
+		var delay time.Duration
+		if retry {
+			var r *fasthttp.Response
+			if err == nil {
+				r = resp
+			}
+			delay, retry = policy.backoff(attempt, r)
+		}
+
+		if policy != nil && policy.OnAttempt != nil {
+			policy.OnAttempt(RetryAttempt{
+				Context:    ctx,
+				Attempt:    attempt,
+				Method:     string(req.Header.Method()),
+				URL:        req.URI().String(),
+				StatusCode: status,
+				Err:        err,
+				WillRetry:  retry,
+				Delay:      delay,
+			})
+		}
+
+		if !retry {
+			return err
+		}
+		if err := sleepContext(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
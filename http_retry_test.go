@@ -0,0 +1,131 @@
+package gocommon
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestHTTPGetJSON_RetryOnServerError(t *testing.T) {
+	var calls int32
+	handler := func(ctx *fasthttp.RequestCtx) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
+			return
+		}
+		ctx.SetStatusCode(fasthttp.StatusOK)
+		ctx.SetBodyString(`{"ok":true}`)
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	var attempts []RetryAttempt
+	resp, status, err := HTTPGetJSON(addr, nil, WithRetry(RetryPolicy{
+		MaxAttempts: 5,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    5 * time.Millisecond,
+		OnAttempt:   func(a RetryAttempt) { attempts = append(attempts, a) },
+	}))
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusOK, status)
+	require.JSONEq(t, `{"ok":true}`, string(resp))
+	require.EqualValues(t, 3, atomic.LoadInt32(&calls))
+
+	require.Len(t, attempts, 3)
+	require.Equal(t, fasthttp.StatusServiceUnavailable, attempts[0].StatusCode)
+	require.True(t, attempts[0].WillRetry)
+	require.Equal(t, 3, attempts[2].Attempt)
+	require.False(t, attempts[2].WillRetry)
+}
+
+func TestHTTPGetJSON_RetryExhausted(t *testing.T) {
+	var calls int32
+	handler := func(ctx *fasthttp.RequestCtx) {
+		atomic.AddInt32(&calls, 1)
+		ctx.SetStatusCode(fasthttp.StatusBadGateway)
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	_, status, err := HTTPGetJSON(addr, nil, WithRetry(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusBadGateway, status)
+	require.EqualValues(t, 3, atomic.LoadInt32(&calls))
+}
+
+func TestHTTPPostJSON_RetryRequiresIdempotencyKey(t *testing.T) {
+	var calls int32
+	var key atomic.Value
+	handler := func(ctx *fasthttp.RequestCtx) {
+		atomic.AddInt32(&calls, 1)
+		key.Store(string(ctx.Request.Header.Peek(IdempotencyKeyHeader)))
+		ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+	_, _, err = HTTPPostJSONWithOptions(addr, nil, map[string]string{"a": "b"}, WithRetry(policy))
+	require.NoError(t, err)
+	require.EqualValues(t, 1, atomic.LoadInt32(&calls))
+
+	atomic.StoreInt32(&calls, 0)
+	_, _, err = HTTPPostJSONWithOptions(addr, nil, map[string]string{"a": "b"}, WithRetry(policy), WithIdempotencyKey("trx-1"))
+	require.NoError(t, err)
+	require.EqualValues(t, 3, atomic.LoadInt32(&calls))
+	require.Equal(t, "trx-1", key.Load())
+}
+
+func TestHTTPClient_RetryHonorsRetryAfter(t *testing.T) {
+	var calls int32
+	handler := func(ctx *fasthttp.RequestCtx) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			ctx.Response.Header.Set("Retry-After", "1")
+			ctx.SetStatusCode(fasthttp.StatusTooManyRequests)
+			return
+		}
+		ctx.SetStatusCode(fasthttp.StatusOK)
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	var delays []time.Duration
+	client := NewHTTPClient(WithRetryPolicy(RetryPolicy{
+		MaxAttempts: 2,
+		MaxDelay:    2 * time.Second,
+		OnAttempt:   func(a RetryAttempt) { delays = append(delays, a.Delay) },
+	}))
+	_, status, err := client.GetJSON(addr, nil)
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusOK, status)
+	require.Equal(t, time.Second, delays[0])
+
+	// Retry-After beyond MaxDelay stops retrying.
+	atomic.StoreInt32(&calls, 0)
+	client = NewHTTPClient(WithRetryPolicy(RetryPolicy{MaxAttempts: 2, MaxDelay: 100 * time.Millisecond}))
+	_, status, err = client.GetJSON(addr, nil)
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusTooManyRequests, status)
+	require.EqualValues(t, 1, atomic.LoadInt32(&calls))
+}
+
+func TestRetryPolicy_BackoffFullJitter(t *testing.T) {
+	p := &RetryPolicy{BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}
+	for attempt := 1; attempt <= 10; attempt++ {
+		d, ok := p.backoff(attempt, nil)
+		require.True(t, ok)
+		require.GreaterOrEqual(t, d, time.Duration(0))
+		require.LessOrEqual(t, d, 50*time.Millisecond)
+		if attempt == 1 {
+			require.LessOrEqual(t, d, 10*time.Millisecond)
+		}
+	}
+}
@@ -0,0 +1,344 @@
+package gocommon
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// SNAPTimestampFormat adalah format X-TIMESTAMP pada standar SNAP BI (ISO 8601 dengan offset zona waktu).
+const SNAPTimestampFormat = "2006-01-02T15:04:05-07:00"
+
+const (
+	defaultTimestampHeader = "X-TIMESTAMP"
+	defaultSignatureHeader = "X-SIGNATURE"
+)
+
+// ErrInvalidSignature dikembalikan (dibungkus) oleh verifier jika signature tidak cocok, tidak ada,
+// atau timestamp berada di luar toleransi. Gunakan errors.Is untuk memeriksanya.
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// Signer menghasilkan signature (base64) atas string-to-sign.
+type Signer interface {
+	Sign(stringToSign []byte) (string, error)
+}
+
+// SignatureVerifier memeriksa signature (base64) atas string-to-sign. Verify mengembalikan error
+// yang membungkus ErrInvalidSignature jika signature tidak cocok.
+type SignatureVerifier interface {
+	Verify(stringToSign []byte, signature string) error
+}
+
+// HMACSHA512Signer menandatangani dan memverifikasi dengan HMAC-SHA512 memakai secret bersama,
+// misalnya client secret pada signature simetris SNAP BI.
+type HMACSHA512Signer struct {
+	Secret []byte
+}
+
+// Sign mengimplementasikan Signer.
+func (s HMACSHA512Signer) Sign(stringToSign []byte) (string, error) {
+	mac := hmac.New(sha512.New, s.Secret)
+	mac.Write(stringToSign)
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// Verify mengimplementasikan SignatureVerifier dengan perbandingan waktu-konstan.
+func (s HMACSHA512Signer) Verify(stringToSign []byte, signature string) error {
+	got, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	mac := hmac.New(sha512.New, s.Secret)
+	mac.Write(stringToSign)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// RSASHA256Signer menandatangani dengan RSA PKCS#1 v1.5 dan SHA-256 (SHA256withRSA), dipakai pada
+// signature asimetris SNAP BI.
+type RSASHA256Signer struct {
+	PrivateKey *rsa.PrivateKey
+}
+
+// Sign mengimplementasikan Signer.
+func (s RSASHA256Signer) Sign(stringToSign []byte) (string, error) {
+	if s.PrivateKey == nil {
+		return "", errors.New("rsa signer: private key is nil")
+	}
+	digest := sha256.Sum256(stringToSign)
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.PrivateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(sig), nil
+}
+
+// RSASHA256Verifier memverifikasi signature SHA256withRSA dengan public key pengirim, misalnya
+// public key bank untuk callback SNAP BI.
+type RSASHA256Verifier struct {
+	PublicKey *rsa.PublicKey
+}
+
+// Verify mengimplementasikan SignatureVerifier.
+func (v RSASHA256Verifier) Verify(stringToSign []byte, signature string) error {
+	if v.PublicKey == nil {
+		return errors.New("rsa verifier: public key is nil")
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	digest := sha256.Sum256(stringToSign)
+	if err := rsa.VerifyPKCS1v15(v.PublicKey, crypto.SHA256, digest[:], sig); err != nil {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// ParseRSAPrivateKeyPEM membaca private key RSA dari PEM berformat PKCS#1 ("RSA PRIVATE KEY")
+// maupun PKCS#8 ("PRIVATE KEY").
+func ParseRSAPrivateKeyPEM(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no pem block found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse private key: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not rsa")
+	}
+	return rsaKey, nil
+}
+
+// ParseRSAPublicKeyPEM membaca public key RSA dari PEM berformat PKIX ("PUBLIC KEY"),
+// PKCS#1 ("RSA PUBLIC KEY"), atau sertifikat X.509 ("CERTIFICATE").
+func ParseRSAPublicKeyPEM(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no pem block found")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+			key = cert.PublicKey
+		}
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse public key: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("public key is not rsa")
+	}
+	return rsaKey, nil
+}
+
+// SignaturePayload adalah komponen permintaan yang ditandatangani.
+type SignaturePayload struct {
+	Method string
+	// Path adalah path relatif beserta query string, misalnya "/v1.0/transfer-va/inquiry".
+	Path string
+	// AccessToken disertakan pada string-to-sign jika tidak kosong (signature simetris SNAP BI).
+	AccessToken string
+	Body        []byte
+	Timestamp   string
+}
+
+// StringToSign menyusun string-to-sign dengan format SNAP BI:
+//
+//	METHOD:Path:AccessToken:Lowercase(Hex(SHA-256(minify(Body)))):Timestamp
+//
+// Bagian AccessToken dihilangkan jika kosong. Body JSON di-minify terlebih dahulu; body lain di-hash apa adanya.
+func (p SignaturePayload) StringToSign() []byte {
+	body := p.Body
+	if len(body) > 0 {
+		var buf bytes.Buffer
+		if json.Compact(&buf, body) == nil {
+			body = buf.Bytes()
+		}
+	}
+	hash := sha256.Sum256(body)
+
+	parts := []string{strings.ToUpper(p.Method), p.Path}
+	if p.AccessToken != "" {
+		parts = append(parts, p.AccessToken)
+	}
+	parts = append(parts, hex.EncodeToString(hash[:]), p.Timestamp)
+	return []byte(strings.Join(parts, ":"))
+}
+
+// SigningConfig berisi pengaturan penandatanganan permintaan keluar dan verifikasi callback masuk.
+type SigningConfig struct {
+	// TimestampHeader adalah nama header timestamp. Default "X-TIMESTAMP".
+	TimestampHeader string
+	// SignatureHeader adalah nama header signature. Default "X-SIGNATURE".
+	SignatureHeader string
+	// IncludeAccessToken menyertakan bearer token dari header Authorization pada string-to-sign,
+	// sesuai signature simetris (HMAC-SHA512) SNAP BI.
+	IncludeAccessToken bool
+	// Now mengembalikan waktu saat ini. Default time.Now.
+	Now func() time.Time
+	// MaxSkew adalah selisih maksimum antara X-TIMESTAMP dan waktu saat ini saat verifikasi.
+	// Nilai nol berarti timestamp tidak diperiksa.
+	MaxSkew time.Duration
+}
+
+// withDefaults mengisi nilai default pada cfg.
+func (cfg SigningConfig) withDefaults() SigningConfig {
+	if cfg.TimestampHeader == "" {
+		cfg.TimestampHeader = defaultTimestampHeader
+	}
+	if cfg.SignatureHeader == "" {
+		cfg.SignatureHeader = defaultSignatureHeader
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	return cfg
+}
+
+// SigningMiddleware menandatangani setiap permintaan keluar dengan signer lalu memasang header
+// timestamp dan signature. Permintaan dengan body stream tidak dapat ditandatangani dan akan gagal.
+//
+// Jika IncludeAccessToken aktif bersama WithTokenSource, pasang WithTokenSource lebih dulu agar
+// header Authorization sudah terisi saat permintaan ditandatangani.
+func SigningMiddleware(signer Signer, cfg SigningConfig) HTTPMiddleware {
+	cfg = cfg.withDefaults()
+	return func(next HTTPHandler) HTTPHandler {
+		return func(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+			if req.IsBodyStream() {
+				return errors.New("cannot sign request with body stream")
+			}
+			payload := SignaturePayload{
+				Method:    string(req.Header.Method()),
+				Path:      string(req.URI().RequestURI()),
+				Body:      req.Body(),
+				Timestamp: cfg.Now().Format(SNAPTimestampFormat),
+			}
+			if cfg.IncludeAccessToken {
+				token, ok := bearerToken(req.Header.Peek("Authorization"))
+				if !ok {
+					return errors.New("cannot sign request: missing bearer token")
+				}
+				payload.AccessToken = token
+			}
+
+			sig, err := signer.Sign(payload.StringToSign())
+			if err != nil {
+				return fmt.Errorf("sign request: %w", err)
+			}
+			req.Header.Set(cfg.TimestampHeader, payload.Timestamp)
+			req.Header.Set(cfg.SignatureHeader, sig)
+			return next(ctx, req, resp)
+		}
+	}
+}
+
+// WithSigner memasang SigningMiddleware pada klien.
+//
+// Contoh penggunaan (signature simetris SNAP BI):
+//
+//	client := NewHTTPClient(
+//	    WithBaseURL("https://api.bank.co.id"),
+//	    WithTokenSource(ts),
+//	    WithSigner(HMACSHA512Signer{Secret: []byte(clientSecret)}, SigningConfig{IncludeAccessToken: true}),
+//	)
+//
+// Untuk HTTPRequest dan helper global lainnya, pasang middleware-nya pada DefaultHTTPClient:
+//
+//	UseHTTPMiddleware(SigningMiddleware(RSASHA256Signer{PrivateKey: key}, SigningConfig{}))
+func WithSigner(signer Signer, cfg SigningConfig) HTTPClientOption {
+	return WithMiddleware(SigningMiddleware(signer, cfg))
+}
+
+// VerifyRequestSignature memverifikasi signature pada callback masuk dengan format yang sama
+// seperti SigningMiddleware. Kembalian error membungkus ErrInvalidSignature jika header tidak ada,
+// timestamp di luar MaxSkew, atau signature tidak cocok.
+//
+// Contoh penggunaan di handler fasthttp:
+//
+//	verifier := RSASHA256Verifier{PublicKey: bankPublicKey}
+//	if err := VerifyRequestSignature(ctx, verifier, SigningConfig{MaxSkew: 5 * time.Minute}); err != nil {
+//	    ctx.SetStatusCode(fasthttp.StatusUnauthorized)
+//	    return
+//	}
+func VerifyRequestSignature(ctx *fasthttp.RequestCtx, verifier SignatureVerifier, cfg SigningConfig) error {
+	cfg = cfg.withDefaults()
+
+	timestamp := string(ctx.Request.Header.Peek(cfg.TimestampHeader))
+	signature := string(ctx.Request.Header.Peek(cfg.SignatureHeader))
+	if timestamp == "" || signature == "" {
+		return fmt.Errorf("%w: missing %s or %s header", ErrInvalidSignature, cfg.TimestampHeader, cfg.SignatureHeader)
+	}
+	if cfg.MaxSkew > 0 {
+		ts, err := time.Parse(time.RFC3339, timestamp)
+		if err != nil {
+			return fmt.Errorf("%w: malformed timestamp %q", ErrInvalidSignature, timestamp)
+		}
+		if skew := cfg.Now().Sub(ts); skew > cfg.MaxSkew || skew < -cfg.MaxSkew {
+			return fmt.Errorf("%w: timestamp outside allowed skew", ErrInvalidSignature)
+		}
+	}
+
+	// Signature dihitung atas body asli, sehingga body yang dikompresi (WithRequestCompression)
+	// di-decode terlebih dahulu.
+	body, err := ctx.Request.BodyUncompressed()
+	if err != nil {
+		return fmt.Errorf("%w: decode body: %v", ErrInvalidSignature, err)
+	}
+	payload := SignaturePayload{
+		Method:    string(ctx.Method()),
+		Path:      string(ctx.RequestURI()),
+		Body:      body,
+		Timestamp: timestamp,
+	}
+	if cfg.IncludeAccessToken {
+		token, ok := bearerToken(ctx.Request.Header.Peek("Authorization"))
+		if !ok {
+			return fmt.Errorf("%w: missing bearer token", ErrInvalidSignature)
+		}
+		payload.AccessToken = token
+	}
+	return verifier.Verify(payload.StringToSign(), signature)
+}
+
+// bearerToken mengambil token dari nilai header Authorization "Bearer <token>".
+func bearerToken(header []byte) (string, bool) {
+	const prefix = "bearer "
+	if len(header) <= len(prefix) || !strings.EqualFold(string(header[:len(prefix)]), prefix) {
+		return "", false
+	}
+	return strings.TrimSpace(string(header[len(prefix):])), true
+}
@@ -0,0 +1,123 @@
+package gocommon
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestSignaturePayload_StringToSign(t *testing.T) {
+	p := SignaturePayload{
+		Method:      "post",
+		Path:        "/v1.0/transfer-va/inquiry",
+		AccessToken: "tok",
+		Body:        []byte("{\n  \"amount\": \"10000.00\"\n}"),
+		Timestamp:   "2024-01-02T15:04:05+07:00",
+	}
+	// sha256(`{"amount":"10000.00"}`)
+	require.Equal(t,
+		"POST:/v1.0/transfer-va/inquiry:tok:b796bc85abeb9568a38d3f52ffd9e9e9c55beb3aa1ae6e79aed76cb93fda00ff:2024-01-02T15:04:05+07:00",
+		string(p.StringToSign()))
+
+	p.AccessToken = ""
+	require.NotContains(t, string(p.StringToSign()), ":tok:")
+}
+
+func TestSigningMiddleware_HMACRoundTrip(t *testing.T) {
+	secret := HMACSHA512Signer{Secret: []byte("client-secret")}
+	cfg := SigningConfig{IncludeAccessToken: true, MaxSkew: time.Minute}
+
+	var verifyErr error
+	handler := func(ctx *fasthttp.RequestCtx) {
+		verifyErr = VerifyRequestSignature(ctx, secret, cfg)
+		if verifyErr != nil {
+			ctx.SetStatusCode(fasthttp.StatusUnauthorized)
+		}
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	client := NewHTTPClient(
+		WithDefaultHeaders(map[string]string{"Authorization": "Bearer tok-1"}),
+		WithSigner(secret, cfg),
+	)
+	_, status, err := client.PostJSON(addr+"/v1.0/transfer?x=1", nil, map[string]string{"amount": "10000.00"})
+	require.NoError(t, err)
+	require.NoError(t, verifyErr)
+	require.Equal(t, fasthttp.StatusOK, status)
+
+	// A different secret must be rejected.
+	other := NewHTTPClient(
+		WithDefaultHeaders(map[string]string{"Authorization": "Bearer tok-1"}),
+		WithSigner(HMACSHA512Signer{Secret: []byte("wrong")}, cfg),
+	)
+	_, status, err = other.PostJSON(addr+"/v1.0/transfer", nil, map[string]string{"amount": "1"})
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusUnauthorized, status)
+	require.ErrorIs(t, verifyErr, ErrInvalidSignature)
+
+	// Signing without a bearer token fails before the request is sent.
+	_, _, err = NewHTTPClient(WithSigner(secret, cfg)).PostJSON(addr, nil, nil)
+	require.ErrorContains(t, err, "missing bearer token")
+}
+
+func TestRSASHA256_SignVerify(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	privPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	pkcs8PEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8})
+	pubDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	pubPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})
+
+	priv, err := ParseRSAPrivateKeyPEM(privPEM)
+	require.NoError(t, err)
+	_, err = ParseRSAPrivateKeyPEM(pkcs8PEM)
+	require.NoError(t, err)
+	pub, err := ParseRSAPublicKeyPEM(pubPEM)
+	require.NoError(t, err)
+	_, err = ParseRSAPublicKeyPEM([]byte("not pem"))
+	require.Error(t, err)
+
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.FixedZone("WIB", 7*3600))
+	var verifyErr error
+	handler := func(ctx *fasthttp.RequestCtx) {
+		verifyErr = VerifyRequestSignature(ctx, RSASHA256Verifier{PublicKey: pub}, SigningConfig{
+			Now:     func() time.Time { return now.Add(10 * time.Second) },
+			MaxSkew: time.Minute,
+		})
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	client := NewHTTPClient(WithSigner(RSASHA256Signer{PrivateKey: priv}, SigningConfig{
+		Now: func() time.Time { return now },
+	}))
+	_, _, err = client.PostJSON(addr+"/callback", nil, map[string]int{"amount": 1})
+	require.NoError(t, err)
+	require.NoError(t, verifyErr)
+
+	stale := NewHTTPClient(WithSigner(RSASHA256Signer{PrivateKey: priv}, SigningConfig{
+		Now: func() time.Time { return now.Add(-time.Hour) },
+	}))
+	_, _, err = stale.PostJSON(addr+"/callback", nil, map[string]int{"amount": 1})
+	require.NoError(t, err)
+	require.True(t, errors.Is(verifyErr, ErrInvalidSignature))
+	require.ErrorContains(t, verifyErr, "skew")
+
+	_, _, err = HTTPPostJSON(addr+"/callback", nil, nil)
+	require.NoError(t, err)
+	require.ErrorContains(t, verifyErr, "missing")
+}
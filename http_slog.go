@@ -0,0 +1,176 @@
+package gocommon
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+const defaultLogBodyLimit = 1024
+
+// HTTPLogConfig berisi pengaturan SlogMiddleware.
+type HTTPLogConfig struct {
+	// Logger adalah tujuan log. Default slog.Default().
+	Logger *slog.Logger
+	// Level adalah level log untuk permintaan yang berhasil. Respons 4xx dicatat dengan level Warn,
+	// sedangkan respons 5xx dan error jaringan dengan level Error. Default slog.LevelInfo.
+	Level slog.Level
+	// LogHeaders menyertakan header permintaan dan respons pada log.
+	LogHeaders bool
+	// LogBodies menyertakan body permintaan dan respons pada log. Body stream tidak pernah dicatat.
+	LogBodies bool
+	// MaxBodySize adalah panjang maksimum body yang dicatat; sisanya dipotong. Default 1024 byte.
+	MaxBodySize int
+	// RedactHeaders adalah header yang nilainya disamarkan. Default Authorization, Proxy-Authorization,
+	// Cookie, Set-Cookie, X-Signature, dan X-Api-Key. Gunakan slice kosong (bukan nil) untuk menonaktifkan.
+	RedactHeaders []string
+	// RedactFields adalah nama field body JSON, body form, dan parameter query yang nilainya disamarkan,
+	// misalnya nomor kartu. Default password, pin, otp, card_number, cvv, access_token, refresh_token,
+	// dan client_secret. Gunakan slice kosong untuk menonaktifkan.
+	RedactFields []string
+}
+
+// SlogMiddleware mencatat setiap percobaan permintaan sebagai log terstruktur log/slog dengan atribut
+// method, url, status, duration, request_bytes, response_bytes, dan request_id (lihat ContextWithRequestID).
+// Header dan field rahasia disamarkan sebelum dicatat.
+//
+// Contoh penggunaan:
+//
+//	client := NewHTTPClient(WithHTTPLogger(HTTPLogConfig{
+//	    Logger:       slog.New(slog.NewJSONHandler(os.Stdout, nil)),
+//	    LogBodies:    true,
+//	    RedactFields: []string{"card_number", "cvv", "password"},
+//	}))
+func SlogMiddleware(cfg HTTPLogConfig) HTTPMiddleware {
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	if cfg.MaxBodySize <= 0 {
+		cfg.MaxBodySize = defaultLogBodyLimit
+	}
+	if cfg.RedactHeaders == nil {
+		cfg.RedactHeaders = defaultRedactHeaders
+	}
+	if cfg.RedactFields == nil {
+		cfg.RedactFields = defaultRedactFields
+	}
+
+	return func(next HTTPHandler) HTTPHandler {
+		return func(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+			start := time.Now()
+			err := next(ctx, req, resp)
+			elapsed := time.Since(start)
+
+			level := cfg.Level
+			attrs := []slog.Attr{
+				slog.String("method", string(req.Header.Method())),
+				slog.String("url", redactURL(req.URI().String(), cfg.RedactFields)),
+				slog.Duration("duration", elapsed),
+				slog.Int("request_bytes", requestSize(req)),
+			}
+			if id := RequestIDFromContext(ctx); id != "" {
+				attrs = append(attrs, slog.String("request_id", id))
+			}
+			if cfg.LogHeaders {
+				attrs = append(attrs, logHeaders("request_headers", requestHeader(req), cfg.RedactHeaders))
+			}
+			if cfg.LogBodies && !req.IsBodyStream() {
+				attrs = append(attrs, slog.String("request_body",
+					cfg.logBody(req.Body(), string(req.Header.ContentType()))))
+			}
+
+			if err != nil {
+				level = slog.LevelError
+				attrs = append(attrs, slog.String("error", err.Error()))
+				cfg.Logger.LogAttrs(ctx, level, "http request failed", attrs...)
+				return err
+			}
+
+			status := resp.StatusCode()
+			switch {
+			case status >= 500:
+				level = slog.LevelError
+			case status >= 400:
+				level = slog.LevelWarn
+			}
+			attrs = append(attrs,
+				slog.Int("status", status),
+				slog.Int("response_bytes", responseSize(resp)),
+			)
+			if cfg.LogHeaders {
+				attrs = append(attrs, logHeaders("response_headers", responseHeader(resp), cfg.RedactHeaders))
+			}
+			// Body stream (misalnya download) tidak dibaca agar tidak mengganggu pemanggil.
+			if cfg.LogBodies && resp.BodyStream() == nil {
+				body, err := resp.BodyUncompressed()
+				if err != nil {
+					body = resp.Body()
+				}
+				attrs = append(attrs, slog.String("response_body",
+					cfg.logBody(body, string(resp.Header.ContentType()))))
+			}
+			cfg.Logger.LogAttrs(ctx, level, "http request", attrs...)
+			return nil
+		}
+	}
+}
+
+// WithHTTPLogger memasang SlogMiddleware pada klien.
+func WithHTTPLogger(cfg HTTPLogConfig) HTTPClientOption {
+	return WithMiddleware(SlogMiddleware(cfg))
+}
+
+// logBody menyamarkan lalu memotong body untuk dicatat.
+func (cfg HTTPLogConfig) logBody(body []byte, contentType string) string {
+	s := redactBody(string(body), contentType, cfg.RedactFields)
+	if len(s) > cfg.MaxBodySize {
+		return s[:cfg.MaxBodySize] + "...(truncated " + strconv.Itoa(len(s)-cfg.MaxBodySize) + " bytes)"
+	}
+	return s
+}
+
+// logHeaders membangun grup atribut header yang sudah disamarkan.
+func logHeaders(key string, h http.Header, redact []string) slog.Attr {
+	redactHeaders(h, redact)
+	attrs := make([]any, 0, len(h))
+	for k, values := range h {
+		if len(values) == 1 {
+			attrs = append(attrs, slog.String(k, values[0]))
+		} else {
+			attrs = append(attrs, slog.Any(k, values))
+		}
+	}
+	return slog.Group(key, attrs...)
+}
+
+// requestHeader menyalin header permintaan fasthttp menjadi http.Header.
+func requestHeader(req *fasthttp.Request) http.Header {
+	h := make(http.Header)
+	for _, key := range req.Header.PeekKeys() {
+		k := string(key)
+		for _, v := range req.Header.PeekAll(k) {
+			h.Add(k, string(v))
+		}
+	}
+	return h
+}
+
+// requestSize mengembalikan ukuran body permintaan, atau Content-Length untuk body stream.
+func requestSize(req *fasthttp.Request) int {
+	if req.IsBodyStream() {
+		return req.Header.ContentLength()
+	}
+	return len(req.Body())
+}
+
+// responseSize mengembalikan ukuran body respons, atau Content-Length untuk body stream.
+func responseSize(resp *fasthttp.Response) int {
+	if resp.BodyStream() != nil {
+		return resp.Header.ContentLength()
+	}
+	return len(resp.Body())
+}
@@ -0,0 +1,79 @@
+package gocommon
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestSlogMiddleware_RedactsAndTruncates(t *testing.T) {
+	handler := func(ctx *fasthttp.RequestCtx) {
+		ctx.SetContentType("application/json")
+		ctx.Response.Header.Set("Set-Cookie", "session=abc")
+		if string(ctx.Path()) == "/big" {
+			ctx.SetBodyString(`{"data":"` + strings.Repeat("x", 100) + `"}`)
+			return
+		}
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetBodyString(`{"error":"declined","card_number":"4111111111111111"}`)
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	var buf bytes.Buffer
+	client := NewHTTPClient(WithHTTPLogger(HTTPLogConfig{
+		Logger:      slog.New(slog.NewJSONHandler(&buf, nil)),
+		LogHeaders:  true,
+		LogBodies:   true,
+		MaxBodySize: 40,
+	}))
+
+	ctx := ContextWithRequestID(t.Context(), "req-1")
+	_, _, err = client.PostJSONContext(ctx, addr+"/pay?access_token=secret", map[string]string{"Authorization": "Bearer secret"},
+		map[string]string{"card_number": "4111111111111111", "cvv": "123", "amount": "10000"})
+	require.NoError(t, err)
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	require.Equal(t, "WARN", entry["level"])
+	require.Equal(t, "POST", entry["method"])
+	require.Equal(t, "req-1", entry["request_id"])
+	require.EqualValues(t, 400, entry["status"])
+	require.Contains(t, entry, "duration")
+	require.Greater(t, entry["request_bytes"], float64(0))
+	require.Greater(t, entry["response_bytes"], float64(0))
+	require.NotContains(t, buf.String(), "4111111111111111")
+	require.NotContains(t, buf.String(), "secret")
+	require.NotContains(t, buf.String(), "session=abc")
+	require.Equal(t, redactedValue, entry["request_headers"].(map[string]interface{})["Authorization"])
+	require.Contains(t, entry["request_body"], `"card_number":"[REDACT`)
+
+	buf.Reset()
+	_, _, err = client.GetJSON(addr+"/big", nil)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	require.Equal(t, "INFO", entry["level"])
+	require.Contains(t, entry["response_body"], "...(truncated")
+	require.Len(t, strings.SplitN(entry["response_body"].(string), "...", 2)[0], 40)
+}
+
+func TestSlogMiddleware_NetworkError(t *testing.T) {
+	var buf bytes.Buffer
+	client := NewHTTPClient(WithHTTPLogger(HTTPLogConfig{Logger: slog.New(slog.NewJSONHandler(&buf, nil))}))
+
+	_, _, err := client.Request("GET", "http://127.0.0.1:1/", nil, nil, 200)
+	require.Error(t, err)
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	require.Equal(t, "ERROR", entry["level"])
+	require.Equal(t, "http request failed", entry["msg"])
+	require.NotEmpty(t, entry["error"])
+	require.NotContains(t, entry, "request_body")
+}
@@ -143,6 +143,25 @@ func TestHTTPGetJSON_InvalidURL(t *testing.T) {
 	_, _, err := HTTPGetJSON("http://invalid.invalid", nil)
 	require.Error(t, err)
 }
+
+func TestHTTPPostJSON_Timeout(t *testing.T) {
+	handler := func(ctx *fasthttp.RequestCtx) {
+		time.Sleep(200 * time.Millisecond)
+		ctx.SetBody(ctx.PostBody())
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	_, _, err = HTTPPostJSON(addr, nil, map[string]string{"foo": "bar"}, 50)
+	require.Error(t, err)
+
+	resp, status, err := HTTPPostJSON(addr, nil, map[string]string{"foo": "bar"}, 5000)
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusOK, status)
+	require.JSONEq(t, `{"foo":"bar"}`, string(resp))
+}
+
 func TestHTTPRequest_GET_Success(t *testing.T) {
 	handler := func(ctx *fasthttp.RequestCtx) {
 		ctx.SetStatusCode(fasthttp.StatusOK)
@@ -0,0 +1,245 @@
+package gocommon
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/valyala/fasthttp/fasthttpproxy"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// ErrPublicKeyPinMismatch dikembalikan (dibungkus error TLS) jika sertifikat server tidak memiliki
+// public key yang cocok dengan pin yang dikonfigurasi melalui WithPinnedPublicKeys.
+var ErrPublicKeyPinMismatch = errors.New("tls: server public key does not match any pin")
+
+// tlsSettings mengumpulkan opsi TLS klien sebelum dibangun menjadi *tls.Config.
+type tlsSettings struct {
+	base       *tls.Config
+	rootCAs    [][]byte
+	certs      []tls.Certificate
+	minVersion uint16
+	pins       [][]byte
+	err        error
+}
+
+// WithProxy mengirim semua permintaan melalui proxy. Skema yang didukung:
+//   - http://[user:pass@]host:port untuk proxy HTTP CONNECT;
+//   - socks5://[user:pass@]host:port untuk proxy SOCKS5.
+//
+// Timeout koneksi ke proxy mengikuti WithDialTimeout. Opsi ini diabaikan jika WithDialFunc diatur.
+//
+// Contoh penggunaan:
+//
+//	client := NewHTTPClient(WithProxy(os.Getenv("EGRESS_PROXY")))
+//	if err := client.Err(); err != nil {
+//	    log.Fatal(err)
+//	}
+func WithProxy(proxyURL string) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.proxyURL = proxyURL
+	}
+}
+
+// WithTLSConfig memakai cfg sebagai dasar konfigurasi TLS. Opsi TLS lain (WithRootCAPEM,
+// WithClientCertificateFile, dan seterusnya) diterapkan pada salinannya.
+func WithTLSConfig(cfg *tls.Config) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.tls.base = cfg
+	}
+}
+
+// WithRootCAPEM menambahkan sertifikat CA (format PEM) yang dipercaya selain CA sistem,
+// misalnya CA privat milik bank.
+func WithRootCAPEM(pem string) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.tls.rootCAs = append(c.tls.rootCAs, []byte(pem))
+	}
+}
+
+// WithRootCAFile sama dengan WithRootCAPEM, tetapi membaca sertifikat dari file.
+func WithRootCAFile(path string) HTTPClientOption {
+	return func(c *HTTPClient) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			c.tls.setErr(fmt.Errorf("read root ca: %w", err))
+			return
+		}
+		c.tls.rootCAs = append(c.tls.rootCAs, data)
+	}
+}
+
+// WithClientCertificatePEM memasang sertifikat klien untuk mutual TLS dari string PEM.
+func WithClientCertificatePEM(certPEM, keyPEM string) HTTPClientOption {
+	return func(c *HTTPClient) {
+		cert, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+		if err != nil {
+			c.tls.setErr(fmt.Errorf("load client certificate: %w", err))
+			return
+		}
+		c.tls.certs = append(c.tls.certs, cert)
+	}
+}
+
+// WithClientCertificateFile memasang sertifikat klien untuk mutual TLS dari file PEM.
+func WithClientCertificateFile(certFile, keyFile string) HTTPClientOption {
+	return func(c *HTTPClient) {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			c.tls.setErr(fmt.Errorf("load client certificate: %w", err))
+			return
+		}
+		c.tls.certs = append(c.tls.certs, cert)
+	}
+}
+
+// WithMinTLSVersion mengatur versi TLS minimum, misalnya tls.VersionTLS12.
+func WithMinTLSVersion(version uint16) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.tls.minVersion = version
+	}
+}
+
+// WithPinnedPublicKeys mengaktifkan public-key pinning. Setiap pin adalah hash SHA-256 dari
+// SubjectPublicKeyInfo sertifikat dalam base64, dengan atau tanpa awalan "sha256/"
+// (format yang sama dengan HPKP). Koneksi diterima jika salah satu sertifikat pada rantai yang sudah
+// diverifikasi cocok. Sertifikat tambahan yang dikirim server tetapi tidak termasuk rantai tersebut
+// diabaikan. Jika InsecureSkipVerify aktif, hanya sertifikat leaf yang diperiksa.
+//
+// Pin dapat dihasilkan dengan:
+//
+//	openssl x509 -in cert.pem -pubkey -noout | openssl pkey -pubin -outform der | openssl dgst -sha256 -binary | base64
+func WithPinnedPublicKeys(pins ...string) HTTPClientOption {
+	return func(c *HTTPClient) {
+		for _, pin := range pins {
+			raw, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(pin, "sha256/"))
+			if err != nil || len(raw) != sha256.Size {
+				c.tls.setErr(fmt.Errorf("invalid public key pin %q", pin))
+				return
+			}
+			c.tls.pins = append(c.tls.pins, raw)
+		}
+	}
+}
+
+// Err mengembalikan error konfigurasi klien, misalnya file sertifikat yang tidak dapat dibaca atau
+// URL proxy yang tidak valid. Jika tidak nil, setiap permintaan melalui klien ini gagal dengan error yang sama.
+func (c *HTTPClient) Err() error {
+	return c.configErr
+}
+
+// setErr menyimpan error pertama yang terjadi saat menerapkan opsi.
+func (s *tlsSettings) setErr(err error) {
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+// build membangun *tls.Config dari opsi yang terkumpul, atau nil jika tidak ada opsi TLS.
+func (s *tlsSettings) build() (*tls.Config, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if s.base == nil && len(s.rootCAs) == 0 && len(s.certs) == 0 && s.minVersion == 0 && len(s.pins) == 0 {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{}
+	if s.base != nil {
+		cfg = s.base.Clone()
+	}
+	if len(s.rootCAs) > 0 {
+		pool := cfg.RootCAs
+		if pool == nil {
+			var err error
+			if pool, err = x509.SystemCertPool(); err != nil {
+				pool = x509.NewCertPool()
+			}
+		}
+		for _, pem := range s.rootCAs {
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, errors.New("no valid certificate found in root ca pem")
+			}
+		}
+		cfg.RootCAs = pool
+	}
+	cfg.Certificates = append(cfg.Certificates, s.certs...)
+	if s.minVersion != 0 {
+		cfg.MinVersion = s.minVersion
+	}
+	if len(s.pins) > 0 {
+		pins := s.pins
+		insecure := cfg.InsecureSkipVerify
+		prev := cfg.VerifyConnection
+		cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+			if prev != nil {
+				if err := prev(cs); err != nil {
+					return err
+				}
+			}
+			return verifyPins(cs, pins, insecure)
+		}
+	}
+	return cfg, nil
+}
+
+// verifyPins memeriksa apakah salah satu sertifikat pada rantai terverifikasi cocok dengan pin.
+// PeerCertificates tidak dipakai karena dapat berisi sertifikat tambahan yang tidak diverifikasi,
+// kecuali leaf (PeerCertificates[0]) saat verifikasi dimatikan dengan InsecureSkipVerify.
+func verifyPins(cs tls.ConnectionState, pins [][]byte, insecure bool) error {
+	var certs []*x509.Certificate
+	for _, chain := range cs.VerifiedChains {
+		certs = append(certs, chain...)
+	}
+	if len(cs.VerifiedChains) == 0 && insecure && len(cs.PeerCertificates) > 0 {
+		certs = cs.PeerCertificates[:1]
+	}
+	for _, cert := range certs {
+		sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+		for _, pin := range pins {
+			if string(sum[:]) == string(pin) {
+				return nil
+			}
+		}
+	}
+	return ErrPublicKeyPinMismatch
+}
+
+// configureTransport menerapkan opsi proxy dan TLS. Dipanggil sekali oleh NewHTTPClient.
+func (c *HTTPClient) configureTransport() error {
+	tlsConfig, err := c.tls.build()
+	if err != nil {
+		return err
+	}
+	c.tlsConfig = tlsConfig
+
+	if c.proxyURL == "" || c.dial != nil {
+		return nil
+	}
+	u, err := url.Parse(c.proxyURL)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid proxy url %q", c.proxyURL)
+	}
+	switch u.Scheme {
+	case "http", "socks5", "socks5h":
+	default:
+		return fmt.Errorf("unsupported proxy scheme %q", u.Scheme)
+	}
+	d := fasthttpproxy.Dialer{
+		Config:         httpproxy.Config{HTTPProxy: c.proxyURL, HTTPSProxy: c.proxyURL},
+		Timeout:        c.dialTimeout,
+		ConnectTimeout: c.dialTimeout,
+	}
+	dial, err := d.GetDialFunc(false)
+	if err != nil {
+		return fmt.Errorf("configure proxy: %w", err)
+	}
+	c.dial = dial
+	return nil
+}
@@ -0,0 +1,347 @@
+package gocommon
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// testCert adalah sertifikat uji beserta key dalam format PEM.
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM string
+	keyPEM  string
+}
+
+// newTestCert membuat sertifikat uji. Jika parent nil, sertifikat ditandatangani sendiri sebagai CA.
+func newTestCert(t *testing.T, cn string, parent *testCert, usage x509.ExtKeyUsage) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	signer, signerKey := tmpl, key
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+	} else {
+		tmpl.ExtKeyUsage = []x509.ExtKeyUsage{usage}
+		tmpl.IPAddresses = []net.IP{net.ParseIP("127.0.0.1")}
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		keyPEM:  string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})),
+	}
+}
+
+// startTLSTestServer menjalankan server HTTPS yang mewajibkan sertifikat klien dari ca. Sertifikat
+// extra ditambahkan di belakang rantai yang dikirim server.
+func startTLSTestServer(t *testing.T, ca, server *testCert, maxVersion uint16, extra ...*testCert) string {
+	t.Helper()
+	pair, err := tls.X509KeyPair([]byte(server.certPEM), []byte(server.keyPEM))
+	require.NoError(t, err)
+	for _, cert := range extra {
+		pair.Certificate = append(pair.Certificate, cert.cert.Raw)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	tlsLn := tls.NewListener(ln, &tls.Config{
+		Certificates: []tls.Certificate{pair},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MaxVersion:   maxVersion,
+	})
+	srv := &fasthttp.Server{Handler: func(ctx *fasthttp.RequestCtx) {
+		ctx.SetBodyString(`{"cn":"` + ctx.TLSConnectionState().PeerCertificates[0].Subject.CommonName + `"}`)
+	}}
+	go srv.Serve(tlsLn)
+	t.Cleanup(func() { ln.Close() })
+	return "https://" + ln.Addr().String()
+}
+
+func TestTLS_MutualTLSWithPrivateCA(t *testing.T) {
+	ca := newTestCert(t, "test-ca", nil, 0)
+	server := newTestCert(t, "server", ca, x509.ExtKeyUsageServerAuth)
+	client := newTestCert(t, "partner-client", ca, x509.ExtKeyUsageClientAuth)
+	addr := startTLSTestServer(t, ca, server, 0)
+
+	c := NewHTTPClient(
+		WithRootCAPEM(ca.certPEM),
+		WithClientCertificatePEM(client.certPEM, client.keyPEM),
+		WithMinTLSVersion(tls.VersionTLS12),
+	)
+	require.NoError(t, c.Err())
+	resp, status, err := c.GetJSON(addr, nil)
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusOK, status)
+	require.JSONEq(t, `{"cn":"partner-client"}`, string(resp))
+
+	// The same configuration loaded from files.
+	dir := t.TempDir()
+	files := map[string]string{"ca.pem": ca.certPEM, "client.pem": client.certPEM, "client.key": client.keyPEM}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+	c = NewHTTPClient(
+		WithRootCAFile(filepath.Join(dir, "ca.pem")),
+		WithClientCertificateFile(filepath.Join(dir, "client.pem"), filepath.Join(dir, "client.key")),
+	)
+	_, status, err = c.GetJSON(addr, nil)
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusOK, status)
+
+	// Without a client certificate the handshake fails.
+	_, _, err = NewHTTPClient(WithRootCAPEM(ca.certPEM)).GetJSON(addr, nil)
+	require.Error(t, err)
+
+	// Without the private CA the server certificate is not trusted.
+	_, _, err = NewHTTPClient(WithClientCertificatePEM(client.certPEM, client.keyPEM)).GetJSON(addr, nil)
+	require.Error(t, err)
+}
+
+func TestTLS_MinVersionAndPinning(t *testing.T) {
+	ca := newTestCert(t, "test-ca", nil, 0)
+	server := newTestCert(t, "server", ca, x509.ExtKeyUsageServerAuth)
+	client := newTestCert(t, "partner-client", ca, x509.ExtKeyUsageClientAuth)
+	addr := startTLSTestServer(t, ca, server, tls.VersionTLS12)
+
+	base := []HTTPClientOption{
+		WithRootCAPEM(ca.certPEM),
+		WithClientCertificatePEM(client.certPEM, client.keyPEM),
+	}
+
+	_, _, err := NewHTTPClient(append(base, WithMinTLSVersion(tls.VersionTLS13))...).GetJSON(addr, nil)
+	require.Error(t, err)
+
+	sum := sha256.Sum256(server.cert.RawSubjectPublicKeyInfo)
+	pin := "sha256/" + base64.StdEncoding.EncodeToString(sum[:])
+	_, status, err := NewHTTPClient(append(base, WithPinnedPublicKeys(pin))...).GetJSON(addr, nil)
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusOK, status)
+
+	other := sha256.Sum256([]byte("other key"))
+	_, _, err = NewHTTPClient(append(base, WithPinnedPublicKeys(base64.StdEncoding.EncodeToString(other[:])))...).GetJSON(addr, nil)
+	require.ErrorContains(t, err, ErrPublicKeyPinMismatch.Error())
+}
+
+func TestTLS_PinningIgnoresUnverifiedCertificates(t *testing.T) {
+	ca := newTestCert(t, "test-ca", nil, 0)
+	server := newTestCert(t, "server", ca, x509.ExtKeyUsageServerAuth)
+	client := newTestCert(t, "partner-client", ca, x509.ExtKeyUsageClientAuth)
+	// Sertifikat yang di-pin bersifat publik, sehingga server lain dapat menyisipkannya ke rantai.
+	otherCA := newTestCert(t, "other-ca", nil, 0)
+	pinned := newTestCert(t, "pinned", otherCA, x509.ExtKeyUsageServerAuth)
+	addr := startTLSTestServer(t, ca, server, 0, pinned)
+
+	sum := sha256.Sum256(pinned.cert.RawSubjectPublicKeyInfo)
+	pin := base64.StdEncoding.EncodeToString(sum[:])
+	_, _, err := NewHTTPClient(
+		WithRootCAPEM(ca.certPEM),
+		WithClientCertificatePEM(client.certPEM, client.keyPEM),
+		WithPinnedPublicKeys(pin),
+	).GetJSON(addr, nil)
+	require.ErrorContains(t, err, ErrPublicKeyPinMismatch.Error())
+
+	// Tanpa verifikasi rantai, hanya sertifikat leaf yang dicocokkan.
+	insecure := []HTTPClientOption{
+		WithTLSConfig(&tls.Config{InsecureSkipVerify: true}),
+		WithClientCertificatePEM(client.certPEM, client.keyPEM),
+	}
+	_, _, err = NewHTTPClient(append(insecure, WithPinnedPublicKeys(pin))...).GetJSON(addr, nil)
+	require.ErrorContains(t, err, ErrPublicKeyPinMismatch.Error())
+
+	sum = sha256.Sum256(server.cert.RawSubjectPublicKeyInfo)
+	_, status, err := NewHTTPClient(append(insecure,
+		WithPinnedPublicKeys(base64.StdEncoding.EncodeToString(sum[:])))...).GetJSON(addr, nil)
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusOK, status)
+}
+
+func TestTLS_ConfigErrors(t *testing.T) {
+	for name, opt := range map[string]HTTPClientOption{
+		"missing ca file":   WithRootCAFile("/nonexistent/ca.pem"),
+		"bad client cert":   WithClientCertificatePEM("not a cert", "not a key"),
+		"bad pin":           WithPinnedPublicKeys("abc"),
+		"bad ca pem":        WithRootCAPEM("garbage"),
+		"unsupported proxy": WithProxy("ftp://proxy:21"),
+	} {
+		c := NewHTTPClient(opt)
+		require.Error(t, c.Err(), name)
+		_, _, err := c.GetJSON("http://127.0.0.1:1", nil)
+		require.Equal(t, c.Err(), err, name)
+	}
+}
+
+func TestProxy_HTTPConnect(t *testing.T) {
+	addr, closeServer, err := startTestServer(func(ctx *fasthttp.RequestCtx) {
+		ctx.SetBodyString(`{"ok":true}`)
+	})
+	require.NoError(t, err)
+	defer closeServer()
+
+	var connects int32
+	var target atomic.Value
+	proxyAddr := startTestProxy(t, func(conn net.Conn) {
+		req, err := http.ReadRequest(bufio.NewReader(conn))
+		if err != nil || req.Method != http.MethodConnect {
+			conn.Close()
+			return
+		}
+		atomic.AddInt32(&connects, 1)
+		target.Store(req.Host)
+		io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n")
+		pipeTo(conn, req.Host)
+	})
+
+	c := NewHTTPClient(WithProxy("http://" + proxyAddr))
+	require.NoError(t, c.Err())
+	resp, _, err := c.GetJSON(addr, nil)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"ok":true}`, string(resp))
+	require.EqualValues(t, 1, atomic.LoadInt32(&connects))
+	require.Equal(t, addr[len("http://"):], target.Load())
+}
+
+func TestProxy_SOCKS5(t *testing.T) {
+	addr, closeServer, err := startTestServer(func(ctx *fasthttp.RequestCtx) {
+		ctx.SetBodyString(`{"ok":true}`)
+	})
+	require.NoError(t, err)
+	defer closeServer()
+
+	var handshakes int32
+	proxyAddr := startTestProxy(t, func(conn net.Conn) {
+		target, err := socks5Handshake(conn)
+		if err != nil {
+			conn.Close()
+			return
+		}
+		atomic.AddInt32(&handshakes, 1)
+		pipeTo(conn, target)
+	})
+
+	c := NewHTTPClient(WithProxy("socks5://" + proxyAddr))
+	require.NoError(t, c.Err())
+	resp, _, err := c.GetJSON(addr, nil)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"ok":true}`, string(resp))
+	require.EqualValues(t, 1, atomic.LoadInt32(&handshakes))
+}
+
+// startTestProxy menjalankan listener TCP yang menyerahkan setiap koneksi ke handle.
+func startTestProxy(t *testing.T, handle func(net.Conn)) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go handle(conn)
+		}
+	}()
+	return ln.Addr().String()
+}
+
+// pipeTo menghubungkan conn dengan target sampai salah satu sisi ditutup.
+func pipeTo(conn net.Conn, target string) {
+	defer conn.Close()
+	upstream, err := net.Dial("tcp", target)
+	if err != nil {
+		return
+	}
+	defer upstream.Close()
+	go io.Copy(upstream, conn)
+	io.Copy(conn, upstream)
+}
+
+// socks5Handshake menjalankan sisi server SOCKS5 tanpa autentikasi dan mengembalikan alamat tujuan.
+func socks5Handshake(conn net.Conn) (string, error) {
+	buf := make([]byte, 262)
+	// Greeting: VER NMETHODS METHODS...
+	if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+		return "", err
+	}
+	if _, err := io.ReadFull(conn, buf[:buf[1]]); err != nil {
+		return "", err
+	}
+	if _, err := conn.Write([]byte{5, 0}); err != nil {
+		return "", err
+	}
+
+	// Request: VER CMD RSV ATYP DST.ADDR DST.PORT
+	if _, err := io.ReadFull(conn, buf[:4]); err != nil {
+		return "", err
+	}
+	var host string
+	switch buf[3] {
+	case 1:
+		if _, err := io.ReadFull(conn, buf[:4]); err != nil {
+			return "", err
+		}
+		host = net.IP(buf[:4]).String()
+	case 3:
+		if _, err := io.ReadFull(conn, buf[:1]); err != nil {
+			return "", err
+		}
+		n := int(buf[0])
+		if _, err := io.ReadFull(conn, buf[:n]); err != nil {
+			return "", err
+		}
+		host = string(buf[:n])
+	default:
+		return "", io.ErrUnexpectedEOF
+	}
+	if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+		return "", err
+	}
+	port := binary.BigEndian.Uint16(buf[:2])
+	if _, err := conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0}); err != nil {
+		return "", err
+	}
+	return net.JoinHostPort(host, strconv.Itoa(int(port))), nil
+}
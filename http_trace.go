@@ -0,0 +1,382 @@
+package gocommon
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"maps"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	// TraceparentHeader adalah header W3C Trace Context yang membawa trace ID, span ID, dan flag.
+	TraceparentHeader = "traceparent"
+	// TracestateHeader adalah header W3C Trace Context yang membawa data vendor.
+	TracestateHeader = "tracestate"
+)
+
+// ErrInvalidTraceparent dikembalikan oleh ParseTraceparent jika header traceparent tidak valid.
+var ErrInvalidTraceparent = errors.New("invalid traceparent")
+
+// TraceID adalah trace ID W3C sepanjang 16 byte.
+type TraceID [16]byte
+
+// SpanID adalah span ID W3C sepanjang 8 byte.
+type SpanID [8]byte
+
+// String mengembalikan trace ID dalam 32 karakter hex huruf kecil.
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// IsValid melaporkan apakah trace ID tidak bernilai nol semua.
+func (id TraceID) IsValid() bool {
+	return id != TraceID{}
+}
+
+// String mengembalikan span ID dalam 16 karakter hex huruf kecil.
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// IsValid melaporkan apakah span ID tidak bernilai nol semua.
+func (id SpanID) IsValid() bool {
+	return id != SpanID{}
+}
+
+// NewTraceID membuat trace ID acak.
+func NewTraceID() TraceID {
+	var id TraceID
+	for !id.IsValid() {
+		rand.Read(id[:])
+	}
+	return id
+}
+
+// NewSpanID membuat span ID acak.
+func NewSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		rand.Read(id[:])
+	}
+	return id
+}
+
+// SpanContext adalah identitas span yang dipropagasikan antar layanan melalui header traceparent
+// dan tracestate.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	// Sampled menandakan span direkam (flag 01 pada traceparent).
+	Sampled bool
+	// TraceState adalah nilai header tracestate apa adanya.
+	TraceState string
+}
+
+// NewSpanContext membuat SpanContext root baru dengan trace ID dan span ID acak yang ditandai sampled.
+func NewSpanContext() SpanContext {
+	return SpanContext{TraceID: NewTraceID(), SpanID: NewSpanID(), Sampled: true}
+}
+
+// IsValid melaporkan apakah trace ID dan span ID valid.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// Traceparent mengembalikan nilai header traceparent versi 00, misalnya
+// "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01".
+func (sc SpanContext) Traceparent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return "00-" + sc.TraceID.String() + "-" + sc.SpanID.String() + "-" + flags
+}
+
+// child mengembalikan SpanContext dengan trace yang sama dan span ID baru.
+func (sc SpanContext) child() SpanContext {
+	sc.SpanID = NewSpanID()
+	return sc
+}
+
+// ParseTraceparent mengurai header traceparent sesuai spesifikasi W3C Trace Context. Versi di atas 00
+// diterima selama awalannya mengikuti format versi 00.
+func ParseTraceparent(value string) (SpanContext, error) {
+	var sc SpanContext
+	if len(value) < 55 || value[2] != '-' || value[35] != '-' || value[52] != '-' {
+		return sc, ErrInvalidTraceparent
+	}
+	version, ok := parseLowerHex(value[0:2])
+	if !ok || version[0] == 0xff || (version[0] == 0 && len(value) != 55) || (len(value) > 55 && value[55] != '-') {
+		return sc, ErrInvalidTraceparent
+	}
+	traceID, ok1 := parseLowerHex(value[3:35])
+	spanID, ok2 := parseLowerHex(value[36:52])
+	flags, ok3 := parseLowerHex(value[53:55])
+	if !ok1 || !ok2 || !ok3 {
+		return sc, ErrInvalidTraceparent
+	}
+	copy(sc.TraceID[:], traceID)
+	copy(sc.SpanID[:], spanID)
+	sc.Sampled = flags[0]&0x01 == 0x01
+	if !sc.IsValid() {
+		return SpanContext{}, ErrInvalidTraceparent
+	}
+	return sc, nil
+}
+
+// parseLowerHex men-decode hex huruf kecil; huruf besar ditolak sesuai spesifikasi.
+func parseLowerHex(s string) ([]byte, bool) {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return nil, false
+		}
+	}
+	b, err := hex.DecodeString(s)
+	return b, err == nil
+}
+
+// spanContextKey adalah kunci context untuk SpanContext.
+type spanContextKey struct{}
+
+// ContextWithSpanContext mengembalikan context turunan yang membawa sc. Permintaan HTTP yang dikirim
+// dengan context ini otomatis membawa header traceparent dan tracestate.
+func ContextWithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, sc)
+}
+
+// SpanContextFromContext mengembalikan SpanContext yang disimpan oleh ContextWithSpanContext.
+func SpanContextFromContext(ctx context.Context) (SpanContext, bool) {
+	if ctx == nil {
+		return SpanContext{}, false
+	}
+	sc, ok := ctx.Value(spanContextKey{}).(SpanContext)
+	return sc, ok && sc.IsValid()
+}
+
+// ExtractTraceContext membaca header traceparent dan tracestate dari permintaan masuk.
+// Nilai false dikembalikan jika header tidak ada atau tidak valid.
+//
+// Contoh penggunaan di handler fasthttp:
+//
+//	func handler(rc *fasthttp.RequestCtx) {
+//	    sc, ok := ExtractTraceContext(rc)
+//	    if !ok {
+//	        sc = NewSpanContext()
+//	    }
+//	    ctx := ContextWithSpanContext(context.Background(), sc)
+//	    resp, status, err := HTTPGetJSONContext(ctx, partnerURL, nil) // traceparent ikut terkirim
+//	}
+func ExtractTraceContext(rc *fasthttp.RequestCtx) (SpanContext, bool) {
+	sc, err := ParseTraceparent(string(rc.Request.Header.Peek(TraceparentHeader)))
+	if err != nil {
+		return SpanContext{}, false
+	}
+	sc.TraceState = string(rc.Request.Header.Peek(TracestateHeader))
+	return sc, true
+}
+
+// InjectTraceContext menulis header traceparent dan tracestate sc ke req.
+func InjectTraceContext(req *fasthttp.Request, sc SpanContext) {
+	if !sc.IsValid() {
+		return
+	}
+	req.Header.Set(TraceparentHeader, sc.Traceparent())
+	if sc.TraceState != "" {
+		req.Header.Set(TracestateHeader, sc.TraceState)
+	} else {
+		req.Header.Del(TracestateHeader)
+	}
+}
+
+// injectContextTrace menyisipkan trace context dari ctx ke req, kecuali pemanggil sudah mengatur
+// header traceparent sendiri.
+func injectContextTrace(ctx context.Context, req *fasthttp.Request) {
+	if len(req.Header.Peek(TraceparentHeader)) > 0 {
+		return
+	}
+	if sc, ok := SpanContextFromContext(ctx); ok {
+		InjectTraceContext(req, sc)
+	}
+}
+
+// Span adalah satu unit kerja yang sudah selesai dan siap diekspor.
+type Span struct {
+	Name         string
+	SpanContext  SpanContext
+	ParentSpanID SpanID
+	Start        time.Time
+	End          time.Time
+	Attributes   map[string]string
+	// Err berisi pesan error jika operasi gagal.
+	Err string
+}
+
+// Duration mengembalikan lama span.
+func (s Span) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// SpanExporter menerima span yang sudah selesai, misalnya untuk dikirim ke collector tracing.
+// Implementasi harus aman dipanggil secara konkuren dan sebaiknya tidak memblokir.
+type SpanExporter interface {
+	ExportSpan(span Span)
+}
+
+// InMemoryExporter menyimpan span di memori, terutama untuk pengujian.
+type InMemoryExporter struct {
+	mu    sync.Mutex
+	spans []Span
+}
+
+// ExportSpan mengimplementasikan SpanExporter.
+func (e *InMemoryExporter) ExportSpan(span Span) {
+	e.mu.Lock()
+	e.spans = append(e.spans, span)
+	e.mu.Unlock()
+}
+
+// Spans mengembalikan salinan span yang sudah diekspor, sesuai urutan selesai.
+func (e *InMemoryExporter) Spans() []Span {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]Span(nil), e.spans...)
+}
+
+// Reset menghapus semua span.
+func (e *InMemoryExporter) Reset() {
+	e.mu.Lock()
+	e.spans = nil
+	e.mu.Unlock()
+}
+
+// Tracer membuat span dan mengirimkannya ke SpanExporter. Hanya span yang sampled yang diekspor.
+type Tracer struct {
+	exporter SpanExporter
+}
+
+// NewTracer membuat Tracer yang mengekspor span ke exporter.
+func NewTracer(exporter SpanExporter) *Tracer {
+	return &Tracer{exporter: exporter}
+}
+
+// ActiveSpan adalah span yang sedang berjalan. Panggil End tepat sekali setelah operasi selesai.
+type ActiveSpan struct {
+	tracer *Tracer
+	span   Span
+	mu     sync.Mutex
+	ended  bool
+}
+
+// Start memulai span baru sebagai anak dari span di ctx, atau sebagai root trace baru jika ctx
+// tidak membawa SpanContext. Context yang dikembalikan membawa SpanContext span baru.
+//
+// Contoh penggunaan:
+//
+//	ctx, span := tracer.Start(ctx, "inquiry")
+//	defer func() { span.End(err) }()
+func (t *Tracer) Start(ctx context.Context, name string) (context.Context, *ActiveSpan) {
+	parent, ok := SpanContextFromContext(ctx)
+	sc := NewSpanContext()
+	if ok {
+		sc = parent.child()
+	}
+	span := &ActiveSpan{
+		tracer: t,
+		span: Span{
+			Name:         name,
+			SpanContext:  sc,
+			ParentSpanID: parent.SpanID,
+			Start:        time.Now(),
+			Attributes:   make(map[string]string),
+		},
+	}
+	return ContextWithSpanContext(ctx, sc), span
+}
+
+// SpanContext mengembalikan identitas span.
+func (s *ActiveSpan) SpanContext() SpanContext {
+	return s.span.SpanContext
+}
+
+// SetAttribute menambahkan atribut pada span.
+func (s *ActiveSpan) SetAttribute(key, value string) {
+	s.mu.Lock()
+	s.span.Attributes[key] = value
+	s.mu.Unlock()
+}
+
+// End menyelesaikan span dan mengekspornya. err yang tidak nil dicatat pada Span.Err.
+// Pemanggilan berikutnya diabaikan.
+func (s *ActiveSpan) End(err error) {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.span.End = time.Now()
+	if err != nil {
+		s.span.Err = err.Error()
+	}
+	span := s.span
+	span.Attributes = maps.Clone(s.span.Attributes)
+	s.mu.Unlock()
+
+	if span.SpanContext.Sampled && s.tracer.exporter != nil {
+		s.tracer.exporter.ExportSpan(span)
+	}
+}
+
+// Middleware mengembalikan HTTPMiddleware yang membuat span klien untuk setiap percobaan permintaan
+// dan mengirim traceparent berisi span ID span tersebut, sehingga layanan tujuan menjadi anak span ini.
+// Atribut yang dicatat: http.method, http.url, dan http.status_code.
+func (t *Tracer) Middleware() HTTPMiddleware {
+	return func(next HTTPHandler) HTTPHandler {
+		return func(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+			// Header asal dipulihkan setelah percobaan agar retry menjadi span saudara, bukan anak.
+			traceparent := string(req.Header.Peek(TraceparentHeader))
+			tracestate := string(req.Header.Peek(TracestateHeader))
+			if _, ok := SpanContextFromContext(ctx); !ok {
+				if parent, err := ParseTraceparent(traceparent); err == nil {
+					parent.TraceState = tracestate
+					ctx = ContextWithSpanContext(ctx, parent)
+				}
+			}
+			method := string(req.Header.Method())
+			ctx, span := t.Start(ctx, "HTTP "+method)
+			span.SetAttribute("http.method", method)
+			span.SetAttribute("http.url", req.URI().String())
+			InjectTraceContext(req, span.SpanContext())
+
+			err := next(ctx, req, resp)
+			restoreHeader(req, TraceparentHeader, traceparent)
+			restoreHeader(req, TracestateHeader, tracestate)
+			if err == nil {
+				span.SetAttribute("http.status_code", strconv.Itoa(resp.StatusCode()))
+			}
+			span.End(err)
+			return err
+		}
+	}
+}
+
+// restoreHeader mengembalikan header key ke value, atau menghapusnya jika value kosong.
+func restoreHeader(req *fasthttp.Request, key, value string) {
+	if value == "" {
+		req.Header.Del(key)
+		return
+	}
+	req.Header.Set(key, value)
+}
+
+// WithTracer memasang middleware t pada klien sehingga setiap permintaan direkam sebagai span.
+// Tanpa opsi ini, trace context dari ctx tetap dipropagasikan tetapi span tidak direkam.
+func WithTracer(t *Tracer) HTTPClientOption {
+	return WithMiddleware(t.Middleware())
+}
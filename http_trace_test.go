@@ -0,0 +1,148 @@
+package gocommon
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	sc, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	require.NoError(t, err)
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID.String())
+	require.Equal(t, "00f067aa0ba902b7", sc.SpanID.String())
+	require.True(t, sc.Sampled)
+	require.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", sc.Traceparent())
+
+	// Versi yang lebih baru boleh membawa field tambahan.
+	sc, err = ParseTraceparent("cc-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-extra")
+	require.NoError(t, err)
+	require.False(t, sc.Sampled)
+
+	for _, bad := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"00_4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	} {
+		_, err := ParseTraceparent(bad)
+		require.ErrorIs(t, err, ErrInvalidTraceparent, bad)
+	}
+
+	root := NewSpanContext()
+	require.True(t, root.IsValid())
+	parsed, err := ParseTraceparent(root.Traceparent())
+	require.NoError(t, err)
+	require.Equal(t, root, parsed)
+}
+
+func TestTraceContext_PropagatesFromContext(t *testing.T) {
+	var mu sync.Mutex
+	var gotParent, gotState string
+	handler := func(ctx *fasthttp.RequestCtx) {
+		mu.Lock()
+		defer mu.Unlock()
+		gotParent = string(ctx.Request.Header.Peek(TraceparentHeader))
+		gotState = string(ctx.Request.Header.Peek(TracestateHeader))
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	sc := NewSpanContext()
+	sc.TraceState = "vendor=abc"
+	ctx := ContextWithSpanContext(context.Background(), sc)
+	_, _, err = HTTPGetJSONContext(ctx, addr, nil)
+	require.NoError(t, err)
+	require.Equal(t, sc.Traceparent(), gotParent)
+	require.Equal(t, "vendor=abc", gotState)
+
+	// Tanpa trace context, header tidak dikirim.
+	_, _, err = HTTPGetJSON(addr, nil)
+	require.NoError(t, err)
+	require.Empty(t, gotParent)
+}
+
+func TestTraceContext_ExtractFromInbound(t *testing.T) {
+	var rc fasthttp.RequestCtx
+	rc.Request.Header.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	rc.Request.Header.Set(TracestateHeader, "congo=t61rcWkgMzE")
+	sc, ok := ExtractTraceContext(&rc)
+	require.True(t, ok)
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID.String())
+	require.Equal(t, "congo=t61rcWkgMzE", sc.TraceState)
+
+	var empty fasthttp.RequestCtx
+	_, ok = ExtractTraceContext(&empty)
+	require.False(t, ok)
+}
+
+func TestTracer_RecordsClientSpans(t *testing.T) {
+	var mu sync.Mutex
+	var received []string
+	attempts := 0
+	handler := func(ctx *fasthttp.RequestCtx) {
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, string(ctx.Request.Header.Peek(TraceparentHeader)))
+		attempts++
+		if attempts == 1 {
+			ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
+		}
+	}
+	addr, closeServer, err := startTestServer(handler)
+	require.NoError(t, err)
+	defer closeServer()
+
+	exporter := &InMemoryExporter{}
+	tracer := NewTracer(exporter)
+	client := NewHTTPClient(WithTracer(tracer), WithRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}))
+
+	ctx, parent := tracer.Start(context.Background(), "inquiry")
+	_, status, err := client.GetJSONContext(ctx, addr+"/v1/inquiry", nil)
+	require.NoError(t, err)
+	require.Equal(t, fasthttp.StatusOK, status)
+	parent.SetAttribute("partner", "bank")
+	parent.End(nil)
+
+	spans := exporter.Spans()
+	require.Len(t, spans, 3)
+	require.Len(t, received, 2)
+	for i, span := range spans[:2] {
+		require.Equal(t, "HTTP GET", span.Name)
+		require.Equal(t, parent.SpanContext().TraceID, span.SpanContext.TraceID)
+		require.Equal(t, parent.SpanContext().SpanID, span.ParentSpanID, "retries are siblings")
+		require.Equal(t, span.SpanContext.Traceparent(), received[i])
+		require.Equal(t, addr+"/v1/inquiry", span.Attributes["http.url"])
+	}
+	require.Equal(t, "503", spans[0].Attributes["http.status_code"])
+	require.Equal(t, "200", spans[1].Attributes["http.status_code"])
+	require.Equal(t, "inquiry", spans[2].Name)
+	require.False(t, spans[2].ParentSpanID.IsValid())
+	require.Equal(t, "bank", spans[2].Attributes["partner"])
+
+	// Tanpa trace context di ctx, span klien menjadi root trace baru.
+	exporter.Reset()
+	_, _, err = client.GetJSON(addr, nil)
+	require.NoError(t, err)
+	spans = exporter.Spans()
+	require.Len(t, spans, 1)
+	require.False(t, spans[0].ParentSpanID.IsValid())
+
+	// Span yang tidak sampled tidak diekspor, tetapi tetap dipropagasikan.
+	exporter.Reset()
+	unsampled := NewSpanContext()
+	unsampled.Sampled = false
+	_, _, err = client.GetJSONContext(ContextWithSpanContext(context.Background(), unsampled), addr, nil)
+	require.NoError(t, err)
+	require.Empty(t, exporter.Spans())
+	require.Contains(t, received[len(received)-1], unsampled.TraceID.String())
+}